@@ -8,6 +8,9 @@ import (
 	"github.com/agnosticeng/agp/cmd/bookkeeper/gc_cleanup"
 	"github.com/agnosticeng/agp/cmd/bookkeeper/gc_mark"
 	"github.com/agnosticeng/agp/internal/async_executor"
+	"github.com/agnosticeng/agp/internal/audit"
+	audit_impl "github.com/agnosticeng/agp/internal/audit/impl"
+	"github.com/agnosticeng/agp/internal/health"
 	"github.com/agnosticeng/agp/internal/process/bookkeeper"
 	"github.com/agnosticeng/agp/internal/query_hasher"
 	"github.com/agnosticeng/cnf"
@@ -15,11 +18,14 @@ import (
 	"github.com/agnosticeng/cnf/providers/file"
 	"github.com/google/uuid"
 	"github.com/urfave/cli/v2"
+	"golang.org/x/sync/errgroup"
 )
 
 type config struct {
 	async_executor.AsyncExecutorConfig
 	bookkeeper.BookkeeperConfig
+	Audit  audit.AuditConfig
+	Health health.HealthConfig
 }
 
 func Command() *cli.Command {
@@ -49,14 +55,31 @@ func Command() *cli.Command {
 				return err
 			}
 
-			aex, err := async_executor.NewAsyncExecutor(sigctx, query_hasher.SHA256QueryHasher, cfg.AsyncExecutorConfig)
+			auditSink, err := audit_impl.NewSink(sigctx, cfg.Audit.Dsn)
+
+			if err != nil {
+				return err
+			}
+
+			defer auditSink.Close()
+
+			aex, err := async_executor.NewAsyncExecutor(sigctx, query_hasher.SHA256QueryHasher, auditSink, cfg.AsyncExecutorConfig)
 
 			if err != nil {
 				return err
 			}
 
 			defer aex.Close()
-			return bookkeeper.Bookkeeper(sigctx, aex, identity.String(), cfg.BookkeeperConfig)
+
+			var (
+				h               = health.NewHealth(sigctx, cfg.Health)
+				group, groupCtx = errgroup.WithContext(sigctx)
+			)
+
+			group.Go(func() error { return h.Serve(groupCtx) })
+			group.Go(func() error { return bookkeeper.Bookkeeper(groupCtx, aex, identity.String(), h, cfg.BookkeeperConfig) })
+
+			return group.Wait()
 		},
 	}
 }
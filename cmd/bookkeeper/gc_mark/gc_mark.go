@@ -42,7 +42,7 @@ func Command() *cli.Command {
 				return err
 			}
 
-			aex, err := async_executor.NewAsyncExecutor(sigctx, query_hasher.SHA256QueryHasher, cfg.AsyncExecutorConfig)
+			aex, err := async_executor.NewAsyncExecutor(sigctx, query_hasher.SHA256QueryHasher, nil, cfg.AsyncExecutorConfig)
 
 			if err != nil {
 				return err
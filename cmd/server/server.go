@@ -6,17 +6,23 @@ import (
 	"syscall"
 
 	"github.com/agnosticeng/agp/internal/async_executor"
+	"github.com/agnosticeng/agp/internal/audit"
+	audit_impl "github.com/agnosticeng/agp/internal/audit/impl"
+	"github.com/agnosticeng/agp/internal/health"
 	"github.com/agnosticeng/agp/internal/process/server"
 	"github.com/agnosticeng/agp/internal/query_hasher"
 	"github.com/agnosticeng/cnf"
 	"github.com/agnosticeng/cnf/providers/env"
 	"github.com/agnosticeng/cnf/providers/file"
 	"github.com/urfave/cli/v2"
+	"golang.org/x/sync/errgroup"
 )
 
 type config struct {
 	async_executor.AsyncExecutorConfig
 	server.ServerConfig
+	Audit  audit.AuditConfig
+	Health health.HealthConfig
 }
 
 func Command() *cli.Command {
@@ -30,7 +36,6 @@ func Command() *cli.Command {
 					cnf.WithProvider(env.NewEnvProvider("AGP")),
 				}
 				aex *async_executor.AsyncExecutor
-				err error
 			)
 
 			defer sigctxcancel()
@@ -43,8 +48,16 @@ func Command() *cli.Command {
 				return err
 			}
 
+			auditSink, err := audit_impl.NewSink(sigctx, cfg.Audit.Dsn)
+
+			if err != nil {
+				return err
+			}
+
+			defer auditSink.Close()
+
 			if len(cfg.Dsn) > 0 {
-				aex, err = async_executor.NewAsyncExecutor(sigctx, query_hasher.SHA256QueryHasher, cfg.AsyncExecutorConfig)
+				aex, err = async_executor.NewAsyncExecutor(sigctx, query_hasher.SHA256QueryHasher, auditSink, cfg.AsyncExecutorConfig)
 
 				if err != nil {
 					return err
@@ -53,7 +66,15 @@ func Command() *cli.Command {
 				defer aex.Close()
 			}
 
-			return server.Server(sigctx, aex, cfg.ServerConfig)
+			var (
+				h               = health.NewHealth(sigctx, cfg.Health)
+				group, groupCtx = errgroup.WithContext(sigctx)
+			)
+
+			group.Go(func() error { return h.Serve(groupCtx) })
+			group.Go(func() error { return server.Server(groupCtx, aex, auditSink, h, cfg.ServerConfig) })
+
+			return group.Wait()
 		},
 	}
 }
@@ -6,6 +6,9 @@ import (
 	"syscall"
 
 	"github.com/agnosticeng/agp/internal/async_executor"
+	"github.com/agnosticeng/agp/internal/audit"
+	audit_impl "github.com/agnosticeng/agp/internal/audit/impl"
+	"github.com/agnosticeng/agp/internal/health"
 	"github.com/agnosticeng/agp/internal/process/bookkeeper"
 	"github.com/agnosticeng/agp/internal/process/server"
 	"github.com/agnosticeng/agp/internal/process/worker"
@@ -24,6 +27,8 @@ type config struct {
 	Worker     worker.WorkerConfig
 	Server     server.ServerConfig
 	Bookkeeper bookkeeper.BookkeeperConfig
+	Audit      audit.AuditConfig
+	Health     health.HealthConfig
 }
 
 func Command() *cli.Command {
@@ -63,7 +68,15 @@ func Command() *cli.Command {
 				}
 			}
 
-			aex, err := async_executor.NewAsyncExecutor(sigctx, query_hasher.SHA256QueryHasher, cfg.AsyncExecutorConfig)
+			auditSink, err := audit_impl.NewSink(sigctx, cfg.Audit.Dsn)
+
+			if err != nil {
+				return err
+			}
+
+			defer auditSink.Close()
+
+			aex, err := async_executor.NewAsyncExecutor(sigctx, query_hasher.SHA256QueryHasher, auditSink, cfg.AsyncExecutorConfig)
 
 			if err != nil {
 				return err
@@ -71,11 +84,15 @@ func Command() *cli.Command {
 
 			defer aex.Close()
 
-			var group, groupCtx = errgroup.WithContext(sigctx)
+			var (
+				h               = health.NewHealth(sigctx, cfg.Health)
+				group, groupCtx = errgroup.WithContext(sigctx)
+			)
 
-			group.Go(func() error { return server.Server(groupCtx, aex, cfg.Server) })
-			group.Go(func() error { return worker.Worker(groupCtx, aex, identity.String(), cfg.Worker) })
-			group.Go(func() error { return bookkeeper.Bookkeeper(groupCtx, aex, identity.String(), cfg.Bookkeeper) })
+			group.Go(func() error { return h.Serve(groupCtx) })
+			group.Go(func() error { return server.Server(groupCtx, aex, auditSink, h, cfg.Server) })
+			group.Go(func() error { return worker.Worker(groupCtx, aex, identity.String(), h, cfg.Worker) })
+			group.Go(func() error { return bookkeeper.Bookkeeper(groupCtx, aex, identity.String(), h, cfg.Bookkeeper) })
 
 			return group.Wait()
 		},
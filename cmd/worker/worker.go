@@ -1,11 +1,15 @@
 package worker
 
 import (
+	"context"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/agnosticeng/agp/internal/async_executor"
+	"github.com/agnosticeng/agp/internal/audit"
+	audit_impl "github.com/agnosticeng/agp/internal/audit/impl"
+	"github.com/agnosticeng/agp/internal/health"
 	"github.com/agnosticeng/agp/internal/process/worker"
 	"github.com/agnosticeng/agp/internal/query_hasher"
 	"github.com/agnosticeng/cnf"
@@ -13,11 +17,14 @@ import (
 	"github.com/agnosticeng/cnf/providers/file"
 	"github.com/google/uuid"
 	"github.com/urfave/cli/v2"
+	"golang.org/x/sync/errgroup"
 )
 
 type config struct {
 	async_executor.AsyncExecutorConfig
 	worker.WorkerConfig
+	Audit  audit.AuditConfig
+	Health health.HealthConfig
 }
 
 func Command() *cli.Command {
@@ -43,14 +50,36 @@ func Command() *cli.Command {
 				return err
 			}
 
-			aex, err := async_executor.NewAsyncExecutor(sigctx, query_hasher.SHA256QueryHasher, cfg.AsyncExecutorConfig)
+			auditSink, err := audit_impl.NewSink(sigctx, cfg.Audit.Dsn)
+
+			if err != nil {
+				return err
+			}
+
+			defer auditSink.Close()
+
+			aex, err := async_executor.NewAsyncExecutor(sigctx, query_hasher.SHA256QueryHasher, auditSink, cfg.AsyncExecutorConfig)
 
 			if err != nil {
 				return err
 			}
 
 			defer aex.Close()
-			return worker.Worker(sigctx, aex, identity.String(), cfg.WorkerConfig)
+
+			var (
+				h                       = health.NewHealth(sigctx, cfg.Health)
+				group, groupCtx         = errgroup.WithContext(sigctx)
+				healthCtx, healthCancel = context.WithCancel(context.WithoutCancel(groupCtx))
+			)
+
+			// the health server outlives the signal so that the drain state stays observable
+			group.Go(func() error { return h.Serve(healthCtx) })
+			group.Go(func() error {
+				defer healthCancel()
+				return worker.Worker(groupCtx, aex, identity.String(), h, cfg.WorkerConfig)
+			})
+
+			return group.Wait()
 		},
 	}
 }
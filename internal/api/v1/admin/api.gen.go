@@ -0,0 +1,917 @@
+//go:build go1.22
+
+// Package admin provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.4.1 DO NOT EDIT.
+package admin
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	externalRef0 "github.com/agnosticeng/agp/internal/api/v1"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/oapi-codegen/runtime"
+	strictnethttp "github.com/oapi-codegen/runtime/strictmiddleware/nethttp"
+)
+
+const (
+	SecretScopes = "Secret.Scopes"
+)
+
+// Defines values for ExecutionStatus.
+const (
+	CANCELED  ExecutionStatus = "CANCELED"
+	EXPIRED   ExecutionStatus = "EXPIRED"
+	FAILED    ExecutionStatus = "FAILED"
+	PENDING   ExecutionStatus = "PENDING"
+	RUNNING   ExecutionStatus = "RUNNING"
+	SUCCEEDED ExecutionStatus = "SUCCEEDED"
+)
+
+// BulkResult defines model for BulkResult.
+type BulkResult struct {
+	Count int64 `json:"count"`
+}
+
+// Execution defines model for Execution.
+type Execution struct {
+	CollapsedCounter int64                  `json:"collapsed_counter"`
+	CompletedAt      *time.Time             `json:"completed_at,omitempty"`
+	CreatedAt        time.Time              `json:"created_at"`
+	CreatedBy        string                 `json:"created_by"`
+	DeadAt           *time.Time             `json:"dead_at,omitempty"`
+	Error            *string                `json:"error,omitempty"`
+	Id               int64                  `json:"id"`
+	PickedAt         *time.Time             `json:"picked_at,omitempty"`
+	PickedBy         *string                `json:"picked_by,omitempty"`
+	Progress         *externalRef0.Progress `json:"progress,omitempty"`
+	Query            string                 `json:"query"`
+	QueryHash        string                 `json:"query_hash"`
+	QueryId          string                 `json:"query_id"`
+	Status           ExecutionStatus        `json:"status"`
+	Tier             string                 `json:"tier"`
+}
+
+// ExecutionFilter defines model for ExecutionFilter.
+type ExecutionFilter struct {
+	CreatedBy *string   `json:"created_by,omitempty"`
+	Ids       *[]int64  `json:"ids,omitempty"`
+	PickedBy  *string   `json:"picked_by,omitempty"`
+	Tiers     *[]string `json:"tiers,omitempty"`
+}
+
+// ExecutionPage defines model for ExecutionPage.
+type ExecutionPage struct {
+	Items      []Execution `json:"items"`
+	NextCursor *string     `json:"next_cursor,omitempty"`
+	TotalCount *int64      `json:"total_count,omitempty"`
+}
+
+// ExecutionStatus defines model for ExecutionStatus.
+type ExecutionStatus string
+
+// Lease defines model for Lease.
+type Lease struct {
+	EndOfTerm time.Time `json:"end_of_term"`
+	Key       string    `json:"key"`
+	Owner     string    `json:"owner"`
+}
+
+// TierStats defines model for TierStats.
+type TierStats struct {
+	OldestPendingAt *time.Time `json:"oldest_pending_at,omitempty"`
+	Pending         int64      `json:"pending"`
+	Running         int64      `json:"running"`
+	Tier            string     `json:"tier"`
+	Workers         int64      `json:"workers"`
+}
+
+// GetExecutionsParams defines parameters for GetExecutions.
+type GetExecutionsParams struct {
+	Status     *[]ExecutionStatus `form:"status,omitempty" json:"status,omitempty"`
+	Tier       *[]string          `form:"tier,omitempty" json:"tier,omitempty"`
+	PickedBy   *string            `form:"picked_by,omitempty" json:"picked_by,omitempty"`
+	CreatedBy  *string            `form:"created_by,omitempty" json:"created_by,omitempty"`
+	Limit      *int32             `form:"limit,omitempty" json:"limit,omitempty"`
+	Cursor     *string            `form:"cursor,omitempty" json:"cursor,omitempty"`
+	TotalCount *bool              `form:"total_count,omitempty" json:"total_count,omitempty"`
+}
+
+// PostExecutionsCancelJSONRequestBody defines body for PostExecutionsCancel for application/json ContentType.
+type PostExecutionsCancelJSONRequestBody = ExecutionFilter
+
+// PostExecutionsRequeueJSONRequestBody defines body for PostExecutionsRequeue for application/json ContentType.
+type PostExecutionsRequeueJSONRequestBody = ExecutionFilter
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+
+	// (GET /executions)
+	GetExecutions(w http.ResponseWriter, r *http.Request, params GetExecutionsParams)
+
+	// (POST /executions/cancel)
+	PostExecutionsCancel(w http.ResponseWriter, r *http.Request)
+
+	// (POST /executions/requeue)
+	PostExecutionsRequeue(w http.ResponseWriter, r *http.Request)
+
+	// (GET /leases)
+	GetLeases(w http.ResponseWriter, r *http.Request)
+
+	// (DELETE /leases/{key})
+	DeleteLeasesKey(w http.ResponseWriter, r *http.Request, key string)
+
+	// (GET /stats)
+	GetStats(w http.ResponseWriter, r *http.Request)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// GetExecutions operation middleware
+func (siw *ServerInterfaceWrapper) GetExecutions(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SecretScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetExecutionsParams
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", r.URL.Query(), &params.Status)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "status", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "tier" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tier", r.URL.Query(), &params.Tier)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tier", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "picked_by" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "picked_by", r.URL.Query(), &params.PickedBy)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "picked_by", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "created_by" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "created_by", r.URL.Query(), &params.CreatedBy)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "created_by", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cursor", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "total_count" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "total_count", r.URL.Query(), &params.TotalCount)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "total_count", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetExecutions(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostExecutionsCancel operation middleware
+func (siw *ServerInterfaceWrapper) PostExecutionsCancel(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SecretScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostExecutionsCancel(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostExecutionsRequeue operation middleware
+func (siw *ServerInterfaceWrapper) PostExecutionsRequeue(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SecretScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostExecutionsRequeue(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetLeases operation middleware
+func (siw *ServerInterfaceWrapper) GetLeases(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SecretScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetLeases(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteLeasesKey operation middleware
+func (siw *ServerInterfaceWrapper) DeleteLeasesKey(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "key" -------------
+	var key string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "key", r.PathValue("key"), &key, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "key", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SecretScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteLeasesKey(w, r, key)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetStats operation middleware
+func (siw *ServerInterfaceWrapper) GetStats(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SecretScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetStats(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshalingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshalingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{})
+}
+
+// ServeMux is an abstraction of http.ServeMux.
+type ServeMux interface {
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+	ServeHTTP(w http.ResponseWriter, r *http.Request)
+}
+
+type StdHTTPServerOptions struct {
+	BaseURL          string
+	BaseRouter       ServeMux
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, m ServeMux) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{
+		BaseRouter: m,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, m ServeMux, baseURL string) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: m,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.Handler {
+	m := options.BaseRouter
+
+	if m == nil {
+		m = http.NewServeMux()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	m.HandleFunc("GET "+options.BaseURL+"/executions", wrapper.GetExecutions)
+	m.HandleFunc("POST "+options.BaseURL+"/executions/cancel", wrapper.PostExecutionsCancel)
+	m.HandleFunc("POST "+options.BaseURL+"/executions/requeue", wrapper.PostExecutionsRequeue)
+	m.HandleFunc("GET "+options.BaseURL+"/leases", wrapper.GetLeases)
+	m.HandleFunc("DELETE "+options.BaseURL+"/leases/{key}", wrapper.DeleteLeasesKey)
+	m.HandleFunc("GET "+options.BaseURL+"/stats", wrapper.GetStats)
+
+	return m
+}
+
+type GetExecutionsRequestObject struct {
+	Params GetExecutionsParams
+}
+
+type GetExecutionsResponseObject interface {
+	VisitGetExecutionsResponse(w http.ResponseWriter) error
+}
+
+type GetExecutions200JSONResponse ExecutionPage
+
+func (response GetExecutions200JSONResponse) VisitGetExecutionsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetExecutions400JSONResponse externalRef0.Error
+
+func (response GetExecutions400JSONResponse) VisitGetExecutionsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostExecutionsCancelRequestObject struct {
+	Body *PostExecutionsCancelJSONRequestBody
+}
+
+type PostExecutionsCancelResponseObject interface {
+	VisitPostExecutionsCancelResponse(w http.ResponseWriter) error
+}
+
+type PostExecutionsCancel200JSONResponse BulkResult
+
+func (response PostExecutionsCancel200JSONResponse) VisitPostExecutionsCancelResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostExecutionsCancel400JSONResponse externalRef0.Error
+
+func (response PostExecutionsCancel400JSONResponse) VisitPostExecutionsCancelResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostExecutionsRequeueRequestObject struct {
+	Body *PostExecutionsRequeueJSONRequestBody
+}
+
+type PostExecutionsRequeueResponseObject interface {
+	VisitPostExecutionsRequeueResponse(w http.ResponseWriter) error
+}
+
+type PostExecutionsRequeue200JSONResponse BulkResult
+
+func (response PostExecutionsRequeue200JSONResponse) VisitPostExecutionsRequeueResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostExecutionsRequeue400JSONResponse externalRef0.Error
+
+func (response PostExecutionsRequeue400JSONResponse) VisitPostExecutionsRequeueResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetLeasesRequestObject struct {
+}
+
+type GetLeasesResponseObject interface {
+	VisitGetLeasesResponse(w http.ResponseWriter) error
+}
+
+type GetLeases200JSONResponse []Lease
+
+func (response GetLeases200JSONResponse) VisitGetLeasesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteLeasesKeyRequestObject struct {
+	Key string `json:"key"`
+}
+
+type DeleteLeasesKeyResponseObject interface {
+	VisitDeleteLeasesKeyResponse(w http.ResponseWriter) error
+}
+
+type DeleteLeasesKey204Response struct {
+}
+
+func (response DeleteLeasesKey204Response) VisitDeleteLeasesKeyResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteLeasesKey404Response struct {
+}
+
+func (response DeleteLeasesKey404Response) VisitDeleteLeasesKeyResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type GetStatsRequestObject struct {
+}
+
+type GetStatsResponseObject interface {
+	VisitGetStatsResponse(w http.ResponseWriter) error
+}
+
+type GetStats200JSONResponse []TierStats
+
+func (response GetStats200JSONResponse) VisitGetStatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+// StrictServerInterface represents all server handlers.
+type StrictServerInterface interface {
+
+	// (GET /executions)
+	GetExecutions(ctx context.Context, request GetExecutionsRequestObject) (GetExecutionsResponseObject, error)
+
+	// (POST /executions/cancel)
+	PostExecutionsCancel(ctx context.Context, request PostExecutionsCancelRequestObject) (PostExecutionsCancelResponseObject, error)
+
+	// (POST /executions/requeue)
+	PostExecutionsRequeue(ctx context.Context, request PostExecutionsRequeueRequestObject) (PostExecutionsRequeueResponseObject, error)
+
+	// (GET /leases)
+	GetLeases(ctx context.Context, request GetLeasesRequestObject) (GetLeasesResponseObject, error)
+
+	// (DELETE /leases/{key})
+	DeleteLeasesKey(ctx context.Context, request DeleteLeasesKeyRequestObject) (DeleteLeasesKeyResponseObject, error)
+
+	// (GET /stats)
+	GetStats(ctx context.Context, request GetStatsRequestObject) (GetStatsResponseObject, error)
+}
+
+type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
+type StrictMiddlewareFunc = strictnethttp.StrictHTTPMiddlewareFunc
+
+type StrictHTTPServerOptions struct {
+	RequestErrorHandlerFunc  func(w http.ResponseWriter, r *http.Request, err error)
+	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: StrictHTTPServerOptions{
+		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		},
+		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		},
+	}}
+}
+
+func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
+}
+
+type strictHandler struct {
+	ssi         StrictServerInterface
+	middlewares []StrictMiddlewareFunc
+	options     StrictHTTPServerOptions
+}
+
+// GetExecutions operation middleware
+func (sh *strictHandler) GetExecutions(w http.ResponseWriter, r *http.Request, params GetExecutionsParams) {
+	var request GetExecutionsRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetExecutions(ctx, request.(GetExecutionsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetExecutions")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetExecutionsResponseObject); ok {
+		if err := validResponse.VisitGetExecutionsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostExecutionsCancel operation middleware
+func (sh *strictHandler) PostExecutionsCancel(w http.ResponseWriter, r *http.Request) {
+	var request PostExecutionsCancelRequestObject
+
+	var body PostExecutionsCancelJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostExecutionsCancel(ctx, request.(PostExecutionsCancelRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostExecutionsCancel")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostExecutionsCancelResponseObject); ok {
+		if err := validResponse.VisitPostExecutionsCancelResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// PostExecutionsRequeue operation middleware
+func (sh *strictHandler) PostExecutionsRequeue(w http.ResponseWriter, r *http.Request) {
+	var request PostExecutionsRequeueRequestObject
+
+	var body PostExecutionsRequeueJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostExecutionsRequeue(ctx, request.(PostExecutionsRequeueRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostExecutionsRequeue")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostExecutionsRequeueResponseObject); ok {
+		if err := validResponse.VisitPostExecutionsRequeueResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetLeases operation middleware
+func (sh *strictHandler) GetLeases(w http.ResponseWriter, r *http.Request) {
+	var request GetLeasesRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetLeases(ctx, request.(GetLeasesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetLeases")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetLeasesResponseObject); ok {
+		if err := validResponse.VisitGetLeasesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeleteLeasesKey operation middleware
+func (sh *strictHandler) DeleteLeasesKey(w http.ResponseWriter, r *http.Request, key string) {
+	var request DeleteLeasesKeyRequestObject
+
+	request.Key = key
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteLeasesKey(ctx, request.(DeleteLeasesKeyRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteLeasesKey")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteLeasesKeyResponseObject); ok {
+		if err := validResponse.VisitDeleteLeasesKeyResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetStats operation middleware
+func (sh *strictHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	var request GetStatsRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetStats(ctx, request.(GetStatsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetStats")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetStatsResponseObject); ok {
+		if err := validResponse.VisitGetStatsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// Base64 encoded, gzipped, json marshaled Swagger object
+var swaggerSpec = []string{
+
+	"H4sIAAAAAAAC/+xXzW7jNhB+FYLtUY2V3aAH37KJNwgaBEbSRQsEgUFLY4tridSSozhCoHcvSEqWFNGO",
+	"nKbtpT7J4vxyvvlm9EIjmeVSgEBNpy9URwlkzD5+KdLNHegiRfMvVzIHhRzsWSQLYV+vpMoY0inlAn89",
+	"owHFMgf3F9agaFUFVMGPgiuI6fShVnzcycnld4iQVgGdPUNUIJfC5y1NWa4hXlh1UKM8Bza1FBDiBesH",
+	"GzOEX5Bn0KppVFysrZYC9l6dZWl0BscxsOPsgVJSeU3xeGTyOY82R2ZRq+xJIldyrUDbivysYEWn9KdJ",
+	"i55JDR3zKpNiMW/Eq4D+KED5jdqTRcJ0cuCYx95DjQyLN8PZ4ereiVcBRQ6+y30FVR7THhh6Ve6E1kui",
+	"/kNrJ7sgAw+ID3bBV57WQH/VC4eRxmMrxREyPRIo9RumFCvfRoFJq+9iKNIzWB3Kcs7WMMxxZ3r3MKrA",
+	"vmQEPOMiKpTe004okaWLd9OZC/BgIe93KAVRZEZpPru9vL69ogG9+3Z7654uzm8vZjezSxrQr+fX7uH+",
+	"28XFbHZpn2d/zq/vZpcdV20ON8C05xZBxAu5WiCobDwFbMBfdrkVY3rGqDfCQS8C3x39zkGZ69HD4GUa",
+	"g8ZFDiLmYn0cizmdkehXhRDjpfcwR0C3Um3qxjgWQzVRNGG3IbVWfZdX0+ysmRT9+8tA67q3YtCR4rkb",
+	"rfSPpCSYADERgEayZZooMEYhPhle56tYG7MHApp3xkQ/pmWJMJaUwHGlVzrjgmemlUJvQeV2rBPX/KMV",
+	"hkxmhhBEheJY3hs2cundQ6QAd9uUUVgCU7bKtYEEMaeV0ediJY0ockzNyflaSI08InMln0tyHmdckPP5",
+	"NQ3oEyjtinh6Ep6Eti1zECzndEo/n4QnnwyMGCY2igk0DGT/rl1Eph7MvLyO6ZReAc5aKaOsWAZogfzw",
+	"Qrnx1cwzwTIHjHqeOfo9nqU7Y/jVnPA7bObo0N2bg8dvsJ1vXasD3PuVeyvA0dopzzj2FGNYMbthn4Zh",
+	"0IPgZ1PNjD07qJ+GYdhB/qkPnXsidsPvHdF2Z6M35hVLNewiWUqZAhO0qh4DqkDnUmjXEJ/C0K3xAsHN",
+	"WZbnKY8sECfftdv5WwejcGRXh8r20NkH2u/RauV+QbeZJhETEaSW3qTGIcNe2HNtWbYe9YSJmNTDnrSm",
+	"SMYwSrhYW9mV3fgMB/ebdC51p0uddepoGTR+kXH58Zdbb59Vn/9RFVD9g7XtfHH+F4W1N1rA/srOC3Rl",
+	"HVdKsmTRhqBsUPBWae9q///X9oNqmwKrM9k3/W6cxN/Me9QAtK48U6of7ORlA2XloJcCeha4O3CShBH7",
+	"QLaJhCdQRG6FJhyHKLu0llyqv0G5Z86bxaHlfrfI9+FxaIQMKf+MTl9sme2Dy1E3y35dj9eZYaGEJrYL",
+	"iJHlZhPSRK6I/ewkW47JjlOl8vThMPsrQPeN8W8Uuf2i2VPozsZor77ZFR8ezRVqUE9NUQqV0imdPJ1O",
+	"mNkBafVY/TUAqbGyY60TAAA=",
+}
+
+// GetSwagger returns the content of the embedded swagger specification file
+// or error if failed to decode
+func decodeSpec() ([]byte, error) {
+	zipped, err := base64.StdEncoding.DecodeString(strings.Join(swaggerSpec, ""))
+	if err != nil {
+		return nil, fmt.Errorf("error base64 decoding spec: %w", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(zipped))
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %w", err)
+	}
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(zr)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+var rawSpec = decodeSpecCached()
+
+// a naive cached of a decoded swagger spec
+func decodeSpecCached() func() ([]byte, error) {
+	data, err := decodeSpec()
+	return func() ([]byte, error) {
+		return data, err
+	}
+}
+
+// Constructs a synthetic filesystem for resolving external references when loading openapi specifications.
+func PathToRawSpec(pathToFile string) map[string]func() ([]byte, error) {
+	res := make(map[string]func() ([]byte, error))
+	if len(pathToFile) > 0 {
+		res[pathToFile] = rawSpec
+	}
+
+	for rawPath, rawFunc := range externalRef0.PathToRawSpec(path.Join(path.Dir(pathToFile), "../common.yaml")) {
+		if _, ok := res[rawPath]; ok {
+			// it is not possible to compare functions in golang, so always overwrite the old value
+		}
+		res[rawPath] = rawFunc
+	}
+	return res
+}
+
+// GetSwagger returns the Swagger specification corresponding to the generated code
+// in this file. The external references of Swagger specification are resolved.
+// The logic of resolving external references is tightly connected to "import-mapping" feature.
+// Externally referenced files must be embedded in the corresponding golang packages.
+// Urls can be supported but this task was out of the scope.
+func GetSwagger() (swagger *openapi3.T, err error) {
+	resolvePath := PathToRawSpec("")
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = func(loader *openapi3.Loader, url *url.URL) ([]byte, error) {
+		pathToFile := url.String()
+		pathToFile = path.Clean(pathToFile)
+		getSpec, ok := resolvePath[pathToFile]
+		if !ok {
+			err1 := fmt.Errorf("path not found: %s", pathToFile)
+			return nil, err1
+		}
+		return getSpec()
+	}
+	var specData []byte
+	specData, err = rawSpec()
+	if err != nil {
+		return
+	}
+	swagger, err = loader.LoadFromData(specData)
+	if err != nil {
+		return
+	}
+	return
+}
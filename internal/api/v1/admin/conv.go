@@ -0,0 +1,74 @@
+package admin
+
+import (
+	v1 "github.com/agnosticeng/agp/internal/api/v1"
+	"github.com/agnosticeng/agp/internal/async_executor"
+	"github.com/agnosticeng/agp/internal/utils"
+	"github.com/samber/lo"
+)
+
+func ToExecution(ex *async_executor.Execution) *Execution {
+	if ex == nil {
+		return nil
+	}
+
+	var res Execution
+
+	res.Id = ex.Id
+	res.CreatedAt = ex.CreatedAt
+	res.CreatedBy = ex.CreatedBy
+	res.QueryId = ex.QueryId
+	res.QueryHash = ex.QueryHash
+	res.Query = ex.Query
+	res.Tier = ex.Tier
+	res.Status = ExecutionStatus(ex.Status)
+	res.CollapsedCounter = ex.CollapsedCounter
+	res.PickedAt = ex.PickedAt
+	res.PickedBy = ex.PickedBy
+	res.DeadAt = ex.DeadAt
+	res.CompletedAt = ex.CompletedAt
+	res.Error = ex.Error
+
+	if ex.Progress != nil {
+		res.Progress = v1.ToProgress(ex.Progress)
+	}
+
+	return &res
+}
+
+func ToExecutionFilter(f *ExecutionFilter) async_executor.ExecutionFilter {
+	if f == nil {
+		return async_executor.ExecutionFilter{}
+	}
+
+	return async_executor.ExecutionFilter{
+		Ids:       utils.Deref(f.Ids),
+		Tiers:     utils.Deref(f.Tiers),
+		PickedBy:  utils.Deref(f.PickedBy),
+		CreatedBy: utils.Deref(f.CreatedBy),
+	}
+}
+
+func ToStatuses(statuses *[]ExecutionStatus) []async_executor.Status {
+	return lo.Map(utils.Deref(statuses), func(s ExecutionStatus, _ int) async_executor.Status {
+		return async_executor.Status(s)
+	})
+}
+
+func ToLease(lease *async_executor.Lease) Lease {
+	return Lease{
+		Key:       lease.Key,
+		Owner:     lease.Owner,
+		EndOfTerm: lease.EndOfTerm,
+	}
+}
+
+func ToTierStats(stats *async_executor.TierStats) TierStats {
+	return TierStats{
+		Tier:            stats.Tier,
+		Pending:         stats.Pending,
+		Running:         stats.Running,
+		Workers:         stats.Workers,
+		OldestPendingAt: stats.OldestPendingAt,
+	}
+}
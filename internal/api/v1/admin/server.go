@@ -0,0 +1,156 @@
+package admin
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/agnosticeng/agp/internal/async_executor"
+	"github.com/agnosticeng/agp/internal/utils"
+	"github.com/agnosticeng/agp/pkg/client_ip_middleware"
+	"github.com/samber/lo"
+	slogctx "github.com/veqryn/slog-context"
+)
+
+type Server struct {
+	logger *slog.Logger
+	aex    *async_executor.AsyncExecutor
+}
+
+func NewServer(ctx context.Context, aex *async_executor.AsyncExecutor) *Server {
+	return &Server{
+		logger: slogctx.FromCtx(ctx),
+		aex:    aex,
+	}
+}
+
+// actor identifies the operator in execution events, the admin API is authenticated with a shared secret.
+func actor(ctx context.Context) string {
+	return "admin:" + client_ip_middleware.FromContext(ctx)
+}
+
+func (srv *Server) GetExecutions(
+	ctx context.Context,
+	request GetExecutionsRequestObject,
+) (GetExecutionsResponseObject, error) {
+	var opts = async_executor.ListExecutionsOptions{
+		ExecutionFilter: async_executor.ExecutionFilter{
+			Statuses:  ToStatuses(request.Params.Status),
+			Tiers:     utils.Deref(request.Params.Tier),
+			PickedBy:  utils.Deref(request.Params.PickedBy),
+			CreatedBy: utils.Deref(request.Params.CreatedBy),
+		},
+		Limit:      int(utils.DerefOr(request.Params.Limit, 100)),
+		TotalCount: utils.DerefOr(request.Params.TotalCount, false),
+	}
+
+	if request.Params.Cursor != nil {
+		cursor, err := async_executor.DecodeExecutionCursor(*request.Params.Cursor)
+
+		if err != nil {
+			return GetExecutions400JSONResponse{Message: err.Error()}, nil
+		}
+
+		opts.After = cursor
+	}
+
+	page, err := srv.aex.ListExecutions(ctx, opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var res = GetExecutions200JSONResponse{
+		Items:      lo.Map(page.Executions, func(ex *async_executor.Execution, _ int) Execution { return *ToExecution(ex) }),
+		TotalCount: page.TotalCount,
+	}
+
+	if page.Next != nil {
+		res.NextCursor = lo.ToPtr(page.Next.Encode())
+	}
+
+	return res, nil
+}
+
+func (srv *Server) PostExecutionsCancel(
+	ctx context.Context,
+	request PostExecutionsCancelRequestObject,
+) (PostExecutionsCancelResponseObject, error) {
+	var filter = ToExecutionFilter(request.Body)
+
+	if filter.IsEmpty() {
+		return PostExecutionsCancel400JSONResponse{Message: "filter must not be empty"}, nil
+	}
+
+	count, err := srv.aex.CancelExecutions(ctx, actor(ctx), filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	srv.logger.Info("executions canceled", "count", count)
+	return PostExecutionsCancel200JSONResponse{Count: count}, nil
+}
+
+func (srv *Server) PostExecutionsRequeue(
+	ctx context.Context,
+	request PostExecutionsRequeueRequestObject,
+) (PostExecutionsRequeueResponseObject, error) {
+	var filter = ToExecutionFilter(request.Body)
+
+	if filter.IsEmpty() {
+		return PostExecutionsRequeue400JSONResponse{Message: "filter must not be empty"}, nil
+	}
+
+	count, err := srv.aex.RequeueExecutions(ctx, actor(ctx), filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	srv.logger.Info("executions requeued", "count", count)
+	return PostExecutionsRequeue200JSONResponse{Count: count}, nil
+}
+
+func (srv *Server) GetLeases(
+	ctx context.Context,
+	request GetLeasesRequestObject,
+) (GetLeasesResponseObject, error) {
+	leases, err := srv.aex.ListLeases(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return GetLeases200JSONResponse(lo.Map(leases, func(l *async_executor.Lease, _ int) Lease { return ToLease(l) })), nil
+}
+
+func (srv *Server) DeleteLeasesKey(
+	ctx context.Context,
+	request DeleteLeasesKeyRequestObject,
+) (DeleteLeasesKeyResponseObject, error) {
+	released, err := srv.aex.ReleaseLease(ctx, request.Key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !released {
+		return DeleteLeasesKey404Response{}, nil
+	}
+
+	srv.logger.Info("lease released", "key", request.Key)
+	return DeleteLeasesKey204Response{}, nil
+}
+
+func (srv *Server) GetStats(
+	ctx context.Context,
+	request GetStatsRequestObject,
+) (GetStatsResponseObject, error) {
+	stats, err := srv.aex.TierStats(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return GetStats200JSONResponse(lo.Map(stats, func(s *async_executor.TierStats, _ int) TierStats { return ToTierStats(s) })), nil
+}
@@ -29,13 +29,47 @@ const (
 	SecretScopes = "Secret.Scopes"
 )
 
+// Defines values for ExecutionEventType.
+const (
+	ExecutionEventTypeCANCELED  ExecutionEventType = "CANCELED"
+	ExecutionEventTypeCOLLAPSED ExecutionEventType = "COLLAPSED"
+	ExecutionEventTypeCOMPLETED ExecutionEventType = "COMPLETED"
+	ExecutionEventTypeCREATED   ExecutionEventType = "CREATED"
+	ExecutionEventTypeDEAD      ExecutionEventType = "DEAD"
+	ExecutionEventTypeEXPIRED   ExecutionEventType = "EXPIRED"
+	ExecutionEventTypePICKED    ExecutionEventType = "PICKED"
+	ExecutionEventTypePROGRESS  ExecutionEventType = "PROGRESS"
+	ExecutionEventTypeREQUEUED  ExecutionEventType = "REQUEUED"
+)
+
 // Defines values for ExecutionStatus.
 const (
-	CANCELED  ExecutionStatus = "CANCELED"
-	FAILED    ExecutionStatus = "FAILED"
-	PENDING   ExecutionStatus = "PENDING"
-	RUNNING   ExecutionStatus = "RUNNING"
-	SUCCEEDED ExecutionStatus = "SUCCEEDED"
+	ExecutionStatusCANCELED  ExecutionStatus = "CANCELED"
+	ExecutionStatusEXPIRED   ExecutionStatus = "EXPIRED"
+	ExecutionStatusFAILED    ExecutionStatus = "FAILED"
+	ExecutionStatusPENDING   ExecutionStatus = "PENDING"
+	ExecutionStatusRUNNING   ExecutionStatus = "RUNNING"
+	ExecutionStatusSUCCEEDED ExecutionStatus = "SUCCEEDED"
+)
+
+// Defines values for IncludedPart.
+const (
+	Preview IncludedPart = "preview"
+)
+
+// Defines values for ResultCompression.
+const (
+	GZIP   ResultCompression = "GZIP"
+	LZ4    ResultCompression = "LZ4"
+	NONE   ResultCompression = "NONE"
+	SNAPPY ResultCompression = "SNAPPY"
+	ZSTD   ResultCompression = "ZSTD"
+)
+
+// Defines values for ResultFormat.
+const (
+	JSON        ResultFormat = "JSON"
+	JSONCOMPACT ResultFormat = "JSON_COMPACT"
 )
 
 // Defines values for SortBy.
@@ -44,35 +78,143 @@ const (
 	CREATEDAT   SortBy = "CREATED_AT"
 )
 
+// Defines values for UsageSource.
+const (
+	ASYNC   UsageSource = "ASYNC"
+	CHPROXY UsageSource = "CHPROXY"
+	SYNC    UsageSource = "SYNC"
+)
+
+// Defines values for UsageGranularity.
+const (
+	DAY   UsageGranularity = "DAY"
+	HOUR  UsageGranularity = "HOUR"
+	MONTH UsageGranularity = "MONTH"
+)
+
+// ColumnStats defines model for ColumnStats.
+type ColumnStats struct {
+	// DistinctCount Approximate number of distinct non-null values.
+	DistinctCount int64 `json:"distinct_count"`
+
+	// Max Largest value, for number, string and time columns.
+	Max *interface{} `json:"max,omitempty"`
+
+	// Min Smallest value, for number, string and time columns.
+	Min       *interface{} `json:"min,omitempty"`
+	Name      string       `json:"name"`
+	NullCount int64        `json:"null_count"`
+
+	// TopValues Most frequent values of string columns with at most 1000 distinct values.
+	TopValues *[]ValueCount `json:"top_values,omitempty"`
+}
+
 // Execution defines model for Execution.
 type Execution struct {
-	CompletedAt *time.Time             `json:"completed_at,omitempty"`
-	CreatedAt   time.Time              `json:"created_at"`
-	Error       *string                `json:"error,omitempty"`
-	Id          int64                  `json:"id"`
-	PickedAt    *time.Time             `json:"picked_at,omitempty"`
-	Progress    *externalRef0.Progress `json:"progress,omitempty"`
-	Query       string                 `json:"query"`
-	QueryHash   string                 `json:"query_hash"`
-	QueryId     string                 `json:"query_id"`
-	Result      *ResultMetadata        `json:"result,omitempty"`
-	Status      ExecutionStatus        `json:"status"`
+	// BackendQueryId Query identifier used on the backend (ClickHouse query_id).
+	BackendQueryId *string `json:"backend_query_id,omitempty"`
+
+	// CollapsedCounter Number of creation requests that were collapsed into this execution.
+	CollapsedCounter *int64     `json:"collapsed_counter,omitempty"`
+	CompletedAt      *time.Time `json:"completed_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+
+	// CreatedBy Quota key of the caller that created the execution, only disclosed to that caller.
+	CreatedBy *string `json:"created_by,omitempty"`
+	Error     *string `json:"error,omitempty"`
+
+	// ExpiresAt Time after which the execution and its result are garbage collected.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Id        int64      `json:"id"`
+	PickedAt  *time.Time `json:"picked_at,omitempty"`
+
+	// PickedBy Identity of the worker that ran the execution.
+	PickedBy *string `json:"picked_by,omitempty"`
+
+	// Pinned Pinned executions are never garbage collected.
+	Pinned *bool `json:"pinned,omitempty"`
+
+	// Preview First rows of the result, returned when requested with include=preview.
+	Preview   *ResultPreview         `json:"preview,omitempty"`
+	Progress  *externalRef0.Progress `json:"progress,omitempty"`
+	Query     string                 `json:"query"`
+	QueryHash string                 `json:"query_hash"`
+	QueryId   string                 `json:"query_id"`
+	Result    *ResultMetadata        `json:"result,omitempty"`
+	Status    ExecutionStatus        `json:"status"`
+	Tier      *string                `json:"tier,omitempty"`
+
+	// Ttl Result retention in seconds requested at creation, the GC defaults apply when absent.
+	Ttl *int64 `json:"ttl,omitempty"`
+}
+
+// ExecutionEvent defines model for ExecutionEvent.
+type ExecutionEvent struct {
+	// Actor Who caused the event, only set when it is the caller.
+	Actor     *string                 `json:"actor,omitempty"`
+	CreatedAt time.Time               `json:"created_at"`
+	Data      *map[string]interface{} `json:"data,omitempty"`
+	Id        int64                   `json:"id"`
+	Status    *ExecutionStatus        `json:"status,omitempty"`
+	Type      ExecutionEventType      `json:"type"`
+}
+
+// ExecutionEventType defines model for ExecutionEventType.
+type ExecutionEventType string
+
+// ExecutionPage defines model for ExecutionPage.
+type ExecutionPage struct {
+	Items      []Execution `json:"items"`
+	NextCursor *string     `json:"next_cursor,omitempty"`
+	TotalCount *int64      `json:"total_count,omitempty"`
 }
 
 // ExecutionStatus defines model for ExecutionStatus.
 type ExecutionStatus string
 
+// IncludedPart defines model for IncludedPart.
+type IncludedPart string
+
 // Query defines model for Query.
 type Query struct {
-	Secrets *[]Secret `json:"secrets,omitempty"`
-	Sql     string    `json:"sql"`
+	Secrets *[]externalRef0.Secret `json:"secrets,omitempty"`
+	Sql     string                 `json:"sql"`
 }
 
+// ResultCompression defines model for ResultCompression.
+type ResultCompression string
+
+// ResultFormat defines model for ResultFormat.
+type ResultFormat string
+
 // ResultMetadata defines model for ResultMetadata.
 type ResultMetadata struct {
-	Duration *int64                 `json:"duration,omitempty"`
-	Meta     *[]externalRef0.Column `json:"meta,omitempty"`
-	Rows     *int64                 `json:"rows,omitempty"`
+	ColumnStats *[]ColumnStats         `json:"column_stats,omitempty"`
+	Duration    *int64                 `json:"duration,omitempty"`
+	Meta        *[]externalRef0.Column `json:"meta,omitempty"`
+	Rows        *int64                 `json:"rows,omitempty"`
+
+	// Size Size of the stored result in bytes.
+	Size               *int64             `json:"size,omitempty"`
+	StorageCompression *ResultCompression `json:"storage_compression,omitempty"`
+	StorageFormat      *ResultFormat      `json:"storage_format,omitempty"`
+}
+
+// ResultPreview First rows of the result, returned when requested with include=preview.
+type ResultPreview struct {
+	// Complete Whether the preview holds all the rows of the result.
+	Complete bool `json:"complete"`
+
+	// Data Rows as objects keyed by column name, in the order of the result schema.
+	Data []interface{} `json:"data"`
+}
+
+// Retention defines model for Retention.
+type Retention struct {
+	Pinned *bool `json:"pinned,omitempty"`
+
+	// Ttl Sets the expiration to now + ttl seconds.
+	Ttl *int64 `json:"ttl,omitempty"`
 }
 
 // SearchQuery defines model for SearchQuery.
@@ -90,33 +232,79 @@ type SearchQueryItem struct {
 // SearchResult defines model for SearchResult.
 type SearchResult = [][]Execution
 
-// Secret defines model for Secret.
-type Secret struct {
-	Key   string `json:"key"`
-	Value string `json:"value"`
-}
-
 // SortBy defines model for SortBy.
 type SortBy string
 
+// Usage defines model for Usage.
+type Usage struct {
+	BytesRead   int64       `json:"bytes_read"`
+	Elapsed     int64       `json:"elapsed"`
+	Period      time.Time   `json:"period"`
+	Queries     int64       `json:"queries"`
+	ResultBytes int64       `json:"result_bytes"`
+	ResultRows  int64       `json:"result_rows"`
+	RowsRead    int64       `json:"rows_read"`
+	Source      UsageSource `json:"source"`
+	Tier        string      `json:"tier"`
+}
+
+// UsageSource defines model for Usage.Source.
+type UsageSource string
+
+// UsageGranularity defines model for UsageGranularity.
+type UsageGranularity string
+
+// ValueCount defines model for ValueCount.
+type ValueCount struct {
+	Count int64  `json:"count"`
+	Value string `json:"value"`
+}
+
 // ExecutionId defines model for ExecutionId.
 type ExecutionId = int64
 
 // Expiration defines model for Expiration.
 type Expiration = int64
 
+// Include defines model for Include.
+type Include = []IncludedPart
+
 // QueryId defines model for QueryId.
 type QueryId = string
 
 // Signature defines model for Signature.
 type Signature = string
 
+// Ttl defines model for Ttl.
+type Ttl = int64
+
+// GetExecutionsParams defines parameters for GetExecutions.
+type GetExecutionsParams struct {
+	Status        *[]ExecutionStatus `form:"status,omitempty" json:"status,omitempty"`
+	Tier          *[]string          `form:"tier,omitempty" json:"tier,omitempty"`
+	QueryIdPrefix *string            `form:"query_id_prefix,omitempty" json:"query_id_prefix,omitempty"`
+	CreatedAfter  *time.Time         `form:"created_after,omitempty" json:"created_after,omitempty"`
+	CreatedBefore *time.Time         `form:"created_before,omitempty" json:"created_before,omitempty"`
+	Limit         *int32             `form:"limit,omitempty" json:"limit,omitempty"`
+	Cursor        *string            `form:"cursor,omitempty" json:"cursor,omitempty"`
+	TotalCount    *bool              `form:"total_count,omitempty" json:"total_count,omitempty"`
+}
+
 // PostExecutionsTextBody defines parameters for PostExecutions.
 type PostExecutionsTextBody = string
 
 // PostExecutionsParams defines parameters for PostExecutions.
 type PostExecutionsParams struct {
 	QueryId *QueryId `form:"query-id,omitempty" json:"query-id,omitempty"`
+
+	// Ttl Result retention in seconds, bounded by the tier maximum.
+	Ttl *Ttl `form:"ttl,omitempty" json:"ttl,omitempty"`
+}
+
+// GetExecutionsExecutionIdParams defines parameters for GetExecutionsExecutionId.
+type GetExecutionsExecutionIdParams struct {
+	// Include Optional parts of executions to return.
+	Include *Include `form:"include,omitempty" json:"include,omitempty"`
 }
 
 // GetExecutionsExecutionIdResultParams defines parameters for GetExecutionsExecutionIdResult.
@@ -125,6 +313,32 @@ type GetExecutionsExecutionIdResultParams struct {
 	QuotaKey   *externalRef0.QuotaKey `form:"quota_key,omitempty" json:"quota_key,omitempty"`
 	Signature  Signature              `form:"signature" json:"signature"`
 	Expiration Expiration             `form:"expiration" json:"expiration"`
+
+	// Format Format to send the result in, the storage format by default; results are sent as stored only in their storage format.
+	Format *ResultFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// AcceptEncoding When it accepts the storage compression of the result (gzip, zstd, lz4 or snappy), the stored bytes are sent as is.
+	AcceptEncoding *string `json:"Accept-Encoding,omitempty"`
+
+	// Range A single byte range, honored when the result is sent as stored (uncompressed storage or accepted storage compression).
+	Range *string `json:"Range,omitempty"`
+
+	// IfRange ETag the Range applies to, the whole result is sent if it changed.
+	IfRange     *string `json:"If-Range,omitempty"`
+	IfNoneMatch *string `json:"If-None-Match,omitempty"`
+}
+
+// PostSearchParams defines parameters for PostSearch.
+type PostSearchParams struct {
+	// Include Optional parts of executions to return.
+	Include *Include `form:"include,omitempty" json:"include,omitempty"`
+}
+
+// GetUsageParams defines parameters for GetUsage.
+type GetUsageParams struct {
+	From        *time.Time        `form:"from,omitempty" json:"from,omitempty"`
+	To          *time.Time        `form:"to,omitempty" json:"to,omitempty"`
+	Granularity *UsageGranularity `form:"granularity,omitempty" json:"granularity,omitempty"`
 }
 
 // PostExecutionsJSONRequestBody defines body for PostExecutions for application/json ContentType.
@@ -133,23 +347,38 @@ type PostExecutionsJSONRequestBody = Query
 // PostExecutionsTextRequestBody defines body for PostExecutions for text/plain ContentType.
 type PostExecutionsTextRequestBody = PostExecutionsTextBody
 
+// PostExecutionsExecutionIdRetentionJSONRequestBody defines body for PostExecutionsExecutionIdRetention for application/json ContentType.
+type PostExecutionsExecutionIdRetentionJSONRequestBody = Retention
+
 // PostSearchJSONRequestBody defines body for PostSearch for application/json ContentType.
 type PostSearchJSONRequestBody = SearchQuery
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
 
+	// (GET /executions)
+	GetExecutions(w http.ResponseWriter, r *http.Request, params GetExecutionsParams)
+
 	// (POST /executions)
 	PostExecutions(w http.ResponseWriter, r *http.Request, params PostExecutionsParams)
 
 	// (GET /executions/{execution_id})
-	GetExecutionsExecutionId(w http.ResponseWriter, r *http.Request, executionId ExecutionId)
+	GetExecutionsExecutionId(w http.ResponseWriter, r *http.Request, executionId ExecutionId, params GetExecutionsExecutionIdParams)
+
+	// (GET /executions/{execution_id}/events)
+	GetExecutionsExecutionIdEvents(w http.ResponseWriter, r *http.Request, executionId ExecutionId)
 
 	// (GET /executions/{execution_id}/result)
 	GetExecutionsExecutionIdResult(w http.ResponseWriter, r *http.Request, executionId ExecutionId, params GetExecutionsExecutionIdResultParams)
 
+	// (POST /executions/{execution_id}/retention)
+	PostExecutionsExecutionIdRetention(w http.ResponseWriter, r *http.Request, executionId ExecutionId)
+
 	// (POST /search)
-	PostSearch(w http.ResponseWriter, r *http.Request)
+	PostSearch(w http.ResponseWriter, r *http.Request, params PostSearchParams)
+
+	// (GET /usage)
+	GetUsage(w http.ResponseWriter, r *http.Request, params GetUsageParams)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -161,6 +390,95 @@ type ServerInterfaceWrapper struct {
 
 type MiddlewareFunc func(http.Handler) http.Handler
 
+// GetExecutions operation middleware
+func (siw *ServerInterfaceWrapper) GetExecutions(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SecretScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetExecutionsParams
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", r.URL.Query(), &params.Status)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "status", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "tier" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tier", r.URL.Query(), &params.Tier)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tier", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "query_id_prefix" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "query_id_prefix", r.URL.Query(), &params.QueryIdPrefix)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "query_id_prefix", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "created_after" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "created_after", r.URL.Query(), &params.CreatedAfter)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "created_after", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "created_before" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "created_before", r.URL.Query(), &params.CreatedBefore)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "created_before", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cursor", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "total_count" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "total_count", r.URL.Query(), &params.TotalCount)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "total_count", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetExecutions(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
 // PostExecutions operation middleware
 func (siw *ServerInterfaceWrapper) PostExecutions(w http.ResponseWriter, r *http.Request) {
 
@@ -183,6 +501,14 @@ func (siw *ServerInterfaceWrapper) PostExecutions(w http.ResponseWriter, r *http
 		return
 	}
 
+	// ------------- Optional query parameter "ttl" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "ttl", r.URL.Query(), &params.Ttl)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "ttl", Err: err})
+		return
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		siw.Handler.PostExecutions(w, r, params)
 	}))
@@ -214,8 +540,50 @@ func (siw *ServerInterfaceWrapper) GetExecutionsExecutionId(w http.ResponseWrite
 
 	r = r.WithContext(ctx)
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetExecutionsExecutionIdParams
+
+	// ------------- Optional query parameter "include" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "include", r.URL.Query(), &params.Include)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "include", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetExecutionsExecutionId(w, r, executionId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetExecutionsExecutionIdEvents operation middleware
+func (siw *ServerInterfaceWrapper) GetExecutionsExecutionIdEvents(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "execution_id" -------------
+	var executionId ExecutionId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "execution_id", r.PathValue("execution_id"), &executionId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "execution_id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SecretScopes, []string{})
+
+	r = r.WithContext(ctx)
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetExecutionsExecutionId(w, r, executionId)
+		siw.Handler.GetExecutionsExecutionIdEvents(w, r, executionId)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -288,6 +656,92 @@ func (siw *ServerInterfaceWrapper) GetExecutionsExecutionIdResult(w http.Respons
 		return
 	}
 
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Accept-Encoding" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Accept-Encoding")]; found {
+		var AcceptEncoding string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Accept-Encoding", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Accept-Encoding", valueList[0], &AcceptEncoding, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Accept-Encoding", Err: err})
+			return
+		}
+
+		params.AcceptEncoding = &AcceptEncoding
+
+	}
+
+	// ------------- Optional header parameter "Range" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Range")]; found {
+		var Range string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Range", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Range", valueList[0], &Range, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Range", Err: err})
+			return
+		}
+
+		params.Range = &Range
+
+	}
+
+	// ------------- Optional header parameter "If-Range" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Range")]; found {
+		var IfRange string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-Range", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Range", valueList[0], &IfRange, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Range", Err: err})
+			return
+		}
+
+		params.IfRange = &IfRange
+
+	}
+
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-None-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-None-Match", valueList[0], &IfNoneMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-None-Match", Err: err})
+			return
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		siw.Handler.GetExecutionsExecutionIdResult(w, r, executionId, params)
 	}))
@@ -299,17 +753,110 @@ func (siw *ServerInterfaceWrapper) GetExecutionsExecutionIdResult(w http.Respons
 	handler.ServeHTTP(w, r)
 }
 
+// PostExecutionsExecutionIdRetention operation middleware
+func (siw *ServerInterfaceWrapper) PostExecutionsExecutionIdRetention(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "execution_id" -------------
+	var executionId ExecutionId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "execution_id", r.PathValue("execution_id"), &executionId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "execution_id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SecretScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostExecutionsExecutionIdRetention(w, r, executionId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
 // PostSearch operation middleware
 func (siw *ServerInterfaceWrapper) PostSearch(w http.ResponseWriter, r *http.Request) {
 
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, SecretScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostSearchParams
+
+	// ------------- Optional query parameter "include" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "include", r.URL.Query(), &params.Include)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "include", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostSearch(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetUsage operation middleware
+func (siw *ServerInterfaceWrapper) GetUsage(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
 	ctx := r.Context()
 
 	ctx = context.WithValue(ctx, SecretScopes, []string{})
 
 	r = r.WithContext(ctx)
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetUsageParams
+
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", r.URL.Query(), &params.From)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "from", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", r.URL.Query(), &params.To)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "to", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "granularity" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "granularity", r.URL.Query(), &params.Granularity)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "granularity", Err: err})
+		return
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostSearch(w, r)
+		siw.Handler.GetUsage(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -439,14 +986,44 @@ func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.H
 		ErrorHandlerFunc:   options.ErrorHandlerFunc,
 	}
 
+	m.HandleFunc("GET "+options.BaseURL+"/executions", wrapper.GetExecutions)
 	m.HandleFunc("POST "+options.BaseURL+"/executions", wrapper.PostExecutions)
 	m.HandleFunc("GET "+options.BaseURL+"/executions/{execution_id}", wrapper.GetExecutionsExecutionId)
+	m.HandleFunc("GET "+options.BaseURL+"/executions/{execution_id}/events", wrapper.GetExecutionsExecutionIdEvents)
 	m.HandleFunc("GET "+options.BaseURL+"/executions/{execution_id}/result", wrapper.GetExecutionsExecutionIdResult)
+	m.HandleFunc("POST "+options.BaseURL+"/executions/{execution_id}/retention", wrapper.PostExecutionsExecutionIdRetention)
 	m.HandleFunc("POST "+options.BaseURL+"/search", wrapper.PostSearch)
+	m.HandleFunc("GET "+options.BaseURL+"/usage", wrapper.GetUsage)
 
 	return m
 }
 
+type GetExecutionsRequestObject struct {
+	Params GetExecutionsParams
+}
+
+type GetExecutionsResponseObject interface {
+	VisitGetExecutionsResponse(w http.ResponseWriter) error
+}
+
+type GetExecutions200JSONResponse ExecutionPage
+
+func (response GetExecutions200JSONResponse) VisitGetExecutionsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetExecutions400JSONResponse externalRef0.Error
+
+func (response GetExecutions400JSONResponse) VisitGetExecutionsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
 type PostExecutionsRequestObject struct {
 	Params   PostExecutionsParams
 	JSONBody *PostExecutionsJSONRequestBody
@@ -466,8 +1043,18 @@ func (response PostExecutions201JSONResponse) VisitPostExecutionsResponse(w http
 	return json.NewEncoder(w).Encode(response)
 }
 
+type PostExecutions400JSONResponse externalRef0.Error
+
+func (response PostExecutions400JSONResponse) VisitPostExecutionsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
 type GetExecutionsExecutionIdRequestObject struct {
 	ExecutionId ExecutionId `json:"execution_id"`
+	Params      GetExecutionsExecutionIdParams
 }
 
 type GetExecutionsExecutionIdResponseObject interface {
@@ -491,6 +1078,31 @@ func (response GetExecutionsExecutionId404Response) VisitGetExecutionsExecutionI
 	return nil
 }
 
+type GetExecutionsExecutionIdEventsRequestObject struct {
+	ExecutionId ExecutionId `json:"execution_id"`
+}
+
+type GetExecutionsExecutionIdEventsResponseObject interface {
+	VisitGetExecutionsExecutionIdEventsResponse(w http.ResponseWriter) error
+}
+
+type GetExecutionsExecutionIdEvents200JSONResponse []ExecutionEvent
+
+func (response GetExecutionsExecutionIdEvents200JSONResponse) VisitGetExecutionsExecutionIdEventsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetExecutionsExecutionIdEvents404Response struct {
+}
+
+func (response GetExecutionsExecutionIdEvents404Response) VisitGetExecutionsExecutionIdEventsResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
 type GetExecutionsExecutionIdResultRequestObject struct {
 	ExecutionId ExecutionId `json:"execution_id"`
 	Params      GetExecutionsExecutionIdResultParams
@@ -519,6 +1131,47 @@ func (response GetExecutionsExecutionIdResult200ApplicationoctetStreamResponse)
 	return err
 }
 
+type GetExecutionsExecutionIdResult206ApplicationoctetStreamResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response GetExecutionsExecutionIdResult206ApplicationoctetStreamResponse) VisitGetExecutionsExecutionIdResultResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/octet-stream")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(206)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type GetExecutionsExecutionIdResult304Response struct {
+}
+
+func (response GetExecutionsExecutionIdResult304Response) VisitGetExecutionsExecutionIdResultResponse(w http.ResponseWriter) error {
+	w.WriteHeader(304)
+	return nil
+}
+
+type GetExecutionsExecutionIdResult307ResponseHeaders struct {
+	Location string
+}
+
+type GetExecutionsExecutionIdResult307Response struct {
+	Headers GetExecutionsExecutionIdResult307ResponseHeaders
+}
+
+func (response GetExecutionsExecutionIdResult307Response) VisitGetExecutionsExecutionIdResultResponse(w http.ResponseWriter) error {
+	w.Header().Set("Location", fmt.Sprint(response.Headers.Location))
+	w.WriteHeader(307)
+	return nil
+}
+
 type GetExecutionsExecutionIdResult404Response struct {
 }
 
@@ -527,8 +1180,69 @@ func (response GetExecutionsExecutionIdResult404Response) VisitGetExecutionsExec
 	return nil
 }
 
+type GetExecutionsExecutionIdResult416Response struct {
+}
+
+func (response GetExecutionsExecutionIdResult416Response) VisitGetExecutionsExecutionIdResultResponse(w http.ResponseWriter) error {
+	w.WriteHeader(416)
+	return nil
+}
+
+type PostExecutionsExecutionIdRetentionRequestObject struct {
+	ExecutionId ExecutionId `json:"execution_id"`
+	Body        *PostExecutionsExecutionIdRetentionJSONRequestBody
+}
+
+type PostExecutionsExecutionIdRetentionResponseObject interface {
+	VisitPostExecutionsExecutionIdRetentionResponse(w http.ResponseWriter) error
+}
+
+type PostExecutionsExecutionIdRetention200JSONResponse Execution
+
+func (response PostExecutionsExecutionIdRetention200JSONResponse) VisitPostExecutionsExecutionIdRetentionResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostExecutionsExecutionIdRetention400JSONResponse externalRef0.Error
+
+func (response PostExecutionsExecutionIdRetention400JSONResponse) VisitPostExecutionsExecutionIdRetentionResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostExecutionsExecutionIdRetention403JSONResponse externalRef0.Error
+
+func (response PostExecutionsExecutionIdRetention403JSONResponse) VisitPostExecutionsExecutionIdRetentionResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostExecutionsExecutionIdRetention404Response struct {
+}
+
+func (response PostExecutionsExecutionIdRetention404Response) VisitPostExecutionsExecutionIdRetentionResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type PostExecutionsExecutionIdRetention409Response struct {
+}
+
+func (response PostExecutionsExecutionIdRetention409Response) VisitPostExecutionsExecutionIdRetentionResponse(w http.ResponseWriter) error {
+	w.WriteHeader(409)
+	return nil
+}
+
 type PostSearchRequestObject struct {
-	Body *PostSearchJSONRequestBody
+	Params PostSearchParams
+	Body   *PostSearchJSONRequestBody
 }
 
 type PostSearchResponseObject interface {
@@ -544,20 +1258,49 @@ func (response PostSearch200JSONResponse) VisitPostSearchResponse(w http.Respons
 	return json.NewEncoder(w).Encode(response)
 }
 
+type GetUsageRequestObject struct {
+	Params GetUsageParams
+}
+
+type GetUsageResponseObject interface {
+	VisitGetUsageResponse(w http.ResponseWriter) error
+}
+
+type GetUsage200JSONResponse []Usage
+
+func (response GetUsage200JSONResponse) VisitGetUsageResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
 // StrictServerInterface represents all server handlers.
 type StrictServerInterface interface {
 
+	// (GET /executions)
+	GetExecutions(ctx context.Context, request GetExecutionsRequestObject) (GetExecutionsResponseObject, error)
+
 	// (POST /executions)
 	PostExecutions(ctx context.Context, request PostExecutionsRequestObject) (PostExecutionsResponseObject, error)
 
 	// (GET /executions/{execution_id})
 	GetExecutionsExecutionId(ctx context.Context, request GetExecutionsExecutionIdRequestObject) (GetExecutionsExecutionIdResponseObject, error)
 
+	// (GET /executions/{execution_id}/events)
+	GetExecutionsExecutionIdEvents(ctx context.Context, request GetExecutionsExecutionIdEventsRequestObject) (GetExecutionsExecutionIdEventsResponseObject, error)
+
 	// (GET /executions/{execution_id}/result)
 	GetExecutionsExecutionIdResult(ctx context.Context, request GetExecutionsExecutionIdResultRequestObject) (GetExecutionsExecutionIdResultResponseObject, error)
 
+	// (POST /executions/{execution_id}/retention)
+	PostExecutionsExecutionIdRetention(ctx context.Context, request PostExecutionsExecutionIdRetentionRequestObject) (PostExecutionsExecutionIdRetentionResponseObject, error)
+
 	// (POST /search)
 	PostSearch(ctx context.Context, request PostSearchRequestObject) (PostSearchResponseObject, error)
+
+	// (GET /usage)
+	GetUsage(ctx context.Context, request GetUsageRequestObject) (GetUsageResponseObject, error)
 }
 
 type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
@@ -589,6 +1332,32 @@ type strictHandler struct {
 	options     StrictHTTPServerOptions
 }
 
+// GetExecutions operation middleware
+func (sh *strictHandler) GetExecutions(w http.ResponseWriter, r *http.Request, params GetExecutionsParams) {
+	var request GetExecutionsRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetExecutions(ctx, request.(GetExecutionsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetExecutions")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetExecutionsResponseObject); ok {
+		if err := validResponse.VisitGetExecutionsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
 // PostExecutions operation middleware
 func (sh *strictHandler) PostExecutions(w http.ResponseWriter, r *http.Request, params PostExecutionsParams) {
 	var request PostExecutionsRequestObject
@@ -634,10 +1403,11 @@ func (sh *strictHandler) PostExecutions(w http.ResponseWriter, r *http.Request,
 }
 
 // GetExecutionsExecutionId operation middleware
-func (sh *strictHandler) GetExecutionsExecutionId(w http.ResponseWriter, r *http.Request, executionId ExecutionId) {
+func (sh *strictHandler) GetExecutionsExecutionId(w http.ResponseWriter, r *http.Request, executionId ExecutionId, params GetExecutionsExecutionIdParams) {
 	var request GetExecutionsExecutionIdRequestObject
 
 	request.ExecutionId = executionId
+	request.Params = params
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
 		return sh.ssi.GetExecutionsExecutionId(ctx, request.(GetExecutionsExecutionIdRequestObject))
@@ -659,6 +1429,32 @@ func (sh *strictHandler) GetExecutionsExecutionId(w http.ResponseWriter, r *http
 	}
 }
 
+// GetExecutionsExecutionIdEvents operation middleware
+func (sh *strictHandler) GetExecutionsExecutionIdEvents(w http.ResponseWriter, r *http.Request, executionId ExecutionId) {
+	var request GetExecutionsExecutionIdEventsRequestObject
+
+	request.ExecutionId = executionId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetExecutionsExecutionIdEvents(ctx, request.(GetExecutionsExecutionIdEventsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetExecutionsExecutionIdEvents")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetExecutionsExecutionIdEventsResponseObject); ok {
+		if err := validResponse.VisitGetExecutionsExecutionIdEventsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
 // GetExecutionsExecutionIdResult operation middleware
 func (sh *strictHandler) GetExecutionsExecutionIdResult(w http.ResponseWriter, r *http.Request, executionId ExecutionId, params GetExecutionsExecutionIdResultParams) {
 	var request GetExecutionsExecutionIdResultRequestObject
@@ -686,10 +1482,45 @@ func (sh *strictHandler) GetExecutionsExecutionIdResult(w http.ResponseWriter, r
 	}
 }
 
+// PostExecutionsExecutionIdRetention operation middleware
+func (sh *strictHandler) PostExecutionsExecutionIdRetention(w http.ResponseWriter, r *http.Request, executionId ExecutionId) {
+	var request PostExecutionsExecutionIdRetentionRequestObject
+
+	request.ExecutionId = executionId
+
+	var body PostExecutionsExecutionIdRetentionJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PostExecutionsExecutionIdRetention(ctx, request.(PostExecutionsExecutionIdRetentionRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostExecutionsExecutionIdRetention")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PostExecutionsExecutionIdRetentionResponseObject); ok {
+		if err := validResponse.VisitPostExecutionsExecutionIdRetentionResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
 // PostSearch operation middleware
-func (sh *strictHandler) PostSearch(w http.ResponseWriter, r *http.Request) {
+func (sh *strictHandler) PostSearch(w http.ResponseWriter, r *http.Request, params PostSearchParams) {
 	var request PostSearchRequestObject
 
+	request.Params = params
+
 	var body PostSearchJSONRequestBody
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
@@ -717,27 +1548,85 @@ func (sh *strictHandler) PostSearch(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// GetUsage operation middleware
+func (sh *strictHandler) GetUsage(w http.ResponseWriter, r *http.Request, params GetUsageParams) {
+	var request GetUsageRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetUsage(ctx, request.(GetUsageRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetUsage")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetUsageResponseObject); ok {
+		if err := validResponse.VisitGetUsageResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/8xXXU/jOBf+K+i872UgZUB70bvSZkfVznQLZa5QVZnk0HpI4mCfsEQo/31lO803bSpm",
-	"pOWqwcfn43nOeWy/gy+iRMQYk4LxOyRMsggJpfny3tBPiYt4HuhPHsMYEkY7cCBmEcIYcG+x4QE4IPEl",
-	"5RIDGJNM0QHl7zBieu+TkBEjGAOP6Y9rcICyBO0nblFCnjvgvSVcMu2tjPaSoszq4UqLzwa71Z5rdbUi",
-	"mc9zU1Tlt3CjSPJ4a7ys+DZmlEr8yI8qDQ4l3HXsiygS8eY2FcT+wuzjNAWxzTNmMMjdPUf5kSvSa4e8",
-	"5PvFZm+YtpEiQUkczZJuqRAJg40moUZHwAjPiUdYUbL37oAvkZ26B6UUsidXB3gwqBEcSLj/fGLURIqt",
-	"RGVq/b/EJxjD/9xqjtwCJbcAfbk3z50C876Ezcpmx9TuwLKtqrMoUaUhHUvnzlh9R2IBI6b3KWKUHi2j",
-	"ZHplzfO83soPYIakzK9RSYNVp+y4Iu66xFY8/kSfwGhAM9j4HTBOIx1n6S1m88VXcODux2Jhf00ni6n3",
-	"zZuBA39O5vbH6sd06nkzb1YLUGF1u2eg2bMKfYlWAzlhdBSUlbHXDosITEqWGVBfwv4ZrGOmjfrKb3HU",
-	"STNIK4Ec0NwRWieDSiradSrCNIr7KpPiHzVUXjuVrZBJf1eiPxDkcs+cMOrLqW3SQSzkETejEeATM1Ny",
-	"5TQquPrSC91n5lEJSZvH7Gh5QtJNVg1iQfE+z4daJ5ftvXaGQdcZ2jZ0rYYs61l/yN1dqTJlBqel0sdf",
-	"l08zVx0an7FfNl9ZmOLxebMnpDXurdBSUVOb6Z03ufdmm8m9lpm/vy+/ecVnn6o0Z6eTvT1ie9K3/ziW",
-	"vdleGPdl3z5oOvEfM0I1UDMwZInC/uMz4jGPND6jvp2D9cEBEsTCzacEpSi66sqWUrKW9LEg4LoLWbis",
-	"G/a5bjflf0ZGtVSgn0pO2UqHs6VWM2Ny0BtumOJ+5XJHlNgbHI+fhGk4TqFemWxjoYj7Z0sp3rKzicpi",
-	"/2yynOtpQanMUQOXF6OLkU5dJBizhMMYri5GF1o49XPAJOGWTwHbf0KZjDTSbP+EgKVQ5FV2TuO98dCP",
-	"a2Xi7u/s+drOByq6EUFm75wxYWxCsiQJuW+Cuj+VPSurW+0h6uzpZHDHN3KTkPHW7p47cfNWb29jiYgL",
-	"Nf8yuvxl6dWE1P45ddTd9/pjLNfOttjDwVesUVB/4Z3KRn2vZaRR9+g31e3A9ehaD+4RANzqVnwSDoWg",
-	"nIpG/YWVO0PNy/fdgC0NvI+bV0/TQb7Lh/WJVAqfkM4VSWTRcEqb4t2ktdI4jXu+NjQrcwU5rCz2mgK/",
-	"Rxvq99dBcz/6xaFrWFm86iiVZ8DDWvOnUL7u+zaVIYzBfb10mRZ3yNf5vwEAAP//v91ymPYRAAA=",
+	"H4sIAAAAAAAC/9Ra3XLbOLJ+FRTOuUjq0LacpObUemsvNIom8Y4jK5a9OzMplwoiWxLGJMABwNjKFN99",
+	"Cz/8ByUq8WZrryxZQKP7Q6P76wb+xCFPUs6AKYkv/sQpESQBBcJ8mz5BmCnK2WWkv1KGL3BK1BYHmJEE",
+	"8AWGYsSSRjjAAv7IqIAIXyiRQYBluIWE6LlrLhKi8AWmTP3wBgdY7VKwX2EDAud5gKdPKRVESytX+yMD",
+	"sasvV4741sUuWRhnEejhEchQ0NQujK/NBxKjlAglEV+j0kiJFEcCVCbYKQ68GlIntq4OVZAYPP9XwBpf",
+	"4P85q0A/s8PkmdMnmhOhcF6qTIQgO6PwR71QbSNaC5uvJ2YXqpWdEKkEZRsjZUE3jKhMQJ8cWQ7Yh3BX",
+	"8K2Ku1jegMxipSEDpv+FKEMSQs4iGaAVz1gEEVrtkNoCUhQESsgTTbKkD12lYrx3oxPK9Hx8ce7d9JAn",
+	"CWfLjxlX5GfY9WPJFVk+wO4AmE7cLQXRJ0pbtVdKXvxoPGTC4yxhC0XccRQ8BaEomG8RlYqyUC1DnjHV",
+	"RXucpoI/0YQoQCxLViC09xazEOPshGVxjD6TOAOpMT54UAKckKfuSldEbEAqKylAay7cggGyhiHCIqRo",
+	"Aig0FslTI4uyrqxFQuL4K4RZgDuABljbWEE0wETF06XFpKvdBy4VWuuDAMypaGKC08wphB6p2iKiUKKH",
+	"n49Gowr2Cu1BceAfevjEaO+LAtWR/GQRaNgbtH3kvhTBV79DaGSWUb3rYSsSPgCLlsaJdUjv4GGiEKKR",
+	"Ps9rfWIzCRHizBxhNx29mMQ0fHjPMwmoEPXytMK+2quQxzFJJURWXxDdFWelJ4cCTOxHZjukkkhtiUKP",
+	"IACVghBliiO1pbKK2wNdXe9GDAqiJWm6TkQUnGgH9JqgtfrKOaudD2GuCHqAnTZZoxrq8yGsrW6i+X9p",
+	"XoA4i3fa48KYawgUd6PNTC/wIAQX3uNjcixIZ09Tt1t9CMlagUCPWxpum3qYc0qVRMKGfSIAbYhYkY3d",
+	"HwgVRI292AuSdb8B+5bS8OHIDXBTfPhfGt9WJfyPXDwU8AvCmiaf+oUzBp6zMzf/r9MJjRCDzyD8ODnJ",
+	"K85jIMyIFvCZwuOhIGLz7twNNvP4RoA8GH1cRpsXw/PAJTSfp9ijvSVyu+dnGnl/tC4yzI4PoEhEFNHz",
+	"pCIqO2hGGeIWdnge2ETs00QdSVyK6AMRKg6kOYPaL95NUARrksVKIpKm8Q49boEhspLA1KAo1Arxhs+V",
+	"QDYgb0SeoOQdDqC9gX/6GZjqRn8SKu4JwP/cchQSE+eN7+vJLuRIUNZCqhCVtWh1+lyR0uy71i2KqOXl",
+	"87rOucfMwXHj633JiBo4zYB9q2d4N7exiUbw4a27desD01T3E57cTMe307c4wJPrq6vxfGE+zy8nP9sP",
+	"N9fvbqaLBQ7wzfTj3fTODf0wv5q6aePZZHplPr6djvWf6S/zy5vpW3zv2ZNSmTnZQNeNSpoziO+Uwrp0",
+	"J8AMntQyzITsyVWKK3IM12tvgFFwL96L0kcKsOfT2dvL2TsN5t1sZj/V8PtpfGk/LO4mk+n07fQQmo3C",
+	"r7ZOEep9cz4WMbmJvIRQgBqOvYv2CzPNh7/8I/ZXPnUU9SAfhjaGTniS6lTi+GZh3ex6NsUBfvfb5RwH",
+	"+LfFrYbp6je9aYvZeD7/1Wu3FfmT2+ZK2t8X1zMcmD9L7dfjye2e+WU+6QBo6fxSKnIEivWazYNhlFU9",
+	"jSH1Fihy7AZaDXyLC/4oh0ZD+sXTDVnQL1AwIam4gKhgd5Sh1U4NriP1ZLKBZdj0h8PZv+5ANTnr0gkO",
+	"i3AOk+e9XjqveFXT/p+okAppGAsUrPmB6wNBZBNgRQpMKejaQH9zh1hj1PY0W2v40i2orSGcgNx0tOVx",
+	"JBGJY6tBRxs/WSx8vEVt9GwikYVA6krDNmGs8yNGEgj07mrxXES29qrWQhbbekF7qFA1ilT1VU+0cFSr",
+	"eyorPt210cveFqCk4+lFv1DXRIw/ov9DSsUFlfO67oEWUkfxBRARbsuIPOjc1uZcKkh8J7c9pINKTBPq",
+	"SjTDOPHF66Y1r195D+K3sHbJhXJF017zuFA/7iqKBbKh56dGbnQJ8z44ki+06ViP75X23Pfu3U1Zizwj",
+	"densp8Wky9qW49s6G9NffanrTnrJlgnBSwFkKOkF2yYZODoFQXk0nK5rtJ1mA6TbgLI0Nhw35Yi8poce",
+	"A5DkmQgb9Hq8+HU20cTE/pm8n99c/+InKD1VZssnHapB2R22S1bw1bUO6ntc7V8TihaWPl83DvROEJbF",
+	"RFDVcMX313c3mvuPf8UB/nA9u33vta7Wm/RQp+ENV9MSPQyTHRbg/lZmk/90dOptERcF3P71XX+1tyhz",
+	"q0+LVlpz8QRkcWTbCX7n0qmhDOiRSCTg93bXp0epQuwehea1Zo8nWjxDoChz5KjvxA1uvesCbvCEvN/o",
+	"Koi3Lk2+mgR1OFACiqAXushAVtOXAeJCizFR3nAyd0Pwol6KFKP3Uqb/GPPfg6krDDuYPoC/ITjwXNuL",
+	"NTu468Y6BEOY6RC10MbaJStVDAKGBQIRJnw6AVulUnuhRtmaGzWoivUv4w3jUtEQzQV/2qGx3LEQjeeX",
+	"WgsQthTB56ej05G2gqfASErxBX59OjrVJErfeRstzqrerf66AU+P/IrKkn0Wg8u+/WpXa5IFaC14Yv5h",
+	"7o0EhMCU7d4D4nEE0jB7DT0pruHxO1DTSougcWX/qedO11Klo++kB3Ctgbee5XI9ofiQwILFLVMBa/q0",
+	"/17WL6JstK1VS7kh1OaQ1BWsuYBnE2vJfV1ayZ5fjTw0392b44vz0ehQCdNjiO2yfQWw9SacV+M1iSV0",
+	"C9T83rCWlDNXILwajSyTYMr1p3UPnYbG989+l7Y2rBYY5LqmR5mbsPDmGeU3cn+e2wVSLo3Y5oGdc7n3",
+	"xPqWqYacFe8+8uDgUP0Gw6JqmMWPPNo9m8FGDZst4EmdpTGhrdmelw3NByR5Z7/Pn3+/v89e50E9F5z9",
+	"WX8HlddSw57QXX9cdaxP1OcO8IvirdP3OXHlDrzRPOcAVmfmOqk/m96YLpvNpzFdQ7gLY3cHZfgWYfV7",
+	"8DKf2uRZpNJadj2QT2vATq1i37Q134r3cVnaaOzJqUfsRnUte5QDO/J9LFb191N5MHR4+XprwJQjD0r1",
+	"Om6Q7PIxYh60/da2nLUDSmBRvX1K3V2xa2S74kAzQ5cw/+pG2ucBEpjSJYZrvptrV1uYUNGS0fdqzv7a",
+	"yM3H9Mw9DWpz4UvCEFJHdQtFat39Vtf4xeYLTQP0RaooQPGXN7pykoyk6e5lUL9dMCVqw3IqS8O2QCIQ",
+	"lWVjo8LJlIU80knnAHtpPZdDkrJNDGZJJAjbQIC2nHFRNPXruybbO/EiY4W1EJUA6HrQKFX7Xw2Ul72m",
+	"3Oj1jzNgeks2Rkcz1zw4oCCR4hbQxy2PO/rTtd66cKtnRL3KXK5PBunTN3nGGZx8ICrc7pVwXHjkoQJ1",
+	"IpUAkhydvF2EMvGv9aapdqdjn/GhiVWg9CtkTbROYWA0A6mSvj02rxNfjX74KlNKZr+ijJhD7KFVPgOK",
+	"66fKmdtXRHmAX9ssoD/8vy/XRlRAaKIWQdocumE65pjK3Po9uru5at+EGViAkVVsXcqiZXb0iofl5ece",
+	"plglqAC/OdfI1dsAOpXk9wczV/3+yBHx1nl5UqAf8OjIs+VCgeMV5VRDKFD5DLDxwk6glDKpD490r+vs",
+	"m5e+51voWkdqLd6UiLy8RSuFopAwdxIR9RCTZt3QyLaFqd9OTp6/UKi0G1QCjP47SwAt/vW/V3xxHkZ/",
+	"qZibNBdWdRfv+oy91DraN5pFwvP7Rf2e9Dt7RuOaryrgsqI/38d17Z3boBabrjyerfuj+LOJ2tQufIYS",
+	"wM5NUf59ChkLt69+yfN2Pigbwp/utXISxOdiezIR4wt89vn8jOhGL87v838NAKnYSojnNAAA",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
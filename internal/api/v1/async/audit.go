@@ -0,0 +1,77 @@
+package async
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	v1 "github.com/agnosticeng/agp/internal/api/v1"
+	"github.com/agnosticeng/agp/internal/audit"
+	"github.com/agnosticeng/agp/internal/query_hasher"
+	"github.com/agnosticeng/agp/pkg/client_ip_middleware"
+)
+
+// AuditMiddleware records one SUBMITTED event per created execution; completion events are
+// recorded by the AsyncExecutor itself.
+func AuditMiddleware(
+	logger *slog.Logger,
+	sink audit.Sink,
+	queryHasher query_hasher.QueryHasher,
+) StrictMiddlewareFunc {
+	return func(f StrictHandlerFunc, operationID string) StrictHandlerFunc {
+		if operationID != "PostExecutions" {
+			return f
+		}
+
+		return func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+			var (
+				claims = v1.ClaimsFromContext(ctx)
+				sql, _ = requestQuery(request.(PostExecutionsRequestObject))
+				ev     = audit.Event{
+					Source:    audit.SourceAsync,
+					Subject:   claims.Subject,
+					QuotaKey:  claims.QuotaKey,
+					ClientIp:  client_ip_middleware.FromContext(ctx),
+					Tier:      claims.Tier,
+					QueryHash: queryHasher(sql),
+					Query:     sql,
+					StartedAt: time.Now(),
+				}
+			)
+
+			res, err := f(ctx, w, r, request)
+
+			switch {
+			case err != nil:
+				ev.Outcome = audit.OutcomeFailed
+				ev.StatusCode = http.StatusInternalServerError
+				ev.Error = err.Error()
+			case res == nil:
+				// an inner middleware already wrote the response, which only happens on admission rejection
+				ev.Outcome = audit.OutcomeRejected
+				ev.StatusCode = http.StatusTooManyRequests
+			default:
+				if invalid, ok := res.(PostExecutions400JSONResponse); ok {
+					ev.Outcome = audit.OutcomeFailed
+					ev.StatusCode = http.StatusBadRequest
+					ev.Error = invalid.Message
+					break
+				}
+
+				if ex, ok := res.(PostExecutions201JSONResponse); ok {
+					ev.ExecutionId = &ex.Id
+				}
+
+				ev.Outcome = audit.OutcomeSubmitted
+				ev.StatusCode = http.StatusCreated
+			}
+
+			if err := sink.Write(context.Background(), ev); err != nil {
+				logger.Error("failed to write audit event", "error", err.Error())
+			}
+
+			return res, err
+		}
+	}
+}
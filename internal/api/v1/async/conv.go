@@ -3,6 +3,7 @@ package async
 import (
 	v1 "github.com/agnosticeng/agp/internal/api/v1"
 	"github.com/agnosticeng/agp/internal/async_executor"
+	"github.com/samber/lo"
 )
 
 func ToResultMetadata(md *async_executor.ResultMetadata) *ResultMetadata {
@@ -23,10 +24,48 @@ func ToResultMetadata(md *async_executor.ResultMetadata) *ResultMetadata {
 	res.Rows = &md.NumRows
 	res.Meta = &meta
 	res.Duration = &durationMs
+	res.Size = &md.StorageSize
+	// results stored before the format was recorded are JSON
+	res.StorageFormat = lo.ToPtr(ResultFormat(lo.CoalesceOrEmpty(md.StorageFormat, async_executor.ResultFormatJSON)))
+	res.StorageCompression = lo.ToPtr(ResultCompression(lo.CoalesceOrEmpty(string(md.StorageCompression), string(NONE))))
+
+	if md.ColumnStats != nil {
+		res.ColumnStats = lo.ToPtr(lo.Map(md.ColumnStats, func(stats async_executor.ColumnStats, _ int) ColumnStats {
+			return ToColumnStats(stats)
+		}))
+	}
+
 	return &res
 }
 
-func ToExecution(ex *async_executor.Execution) *Execution {
+func ToColumnStats(stats async_executor.ColumnStats) ColumnStats {
+	var res = ColumnStats{
+		Name:          stats.Name,
+		NullCount:     stats.NullCount,
+		DistinctCount: stats.DistinctCount,
+	}
+
+	// raw JSON values keep 64-bit integers exact
+	if stats.Min != nil {
+		res.Min = lo.ToPtr[any](stats.Min)
+	}
+
+	if stats.Max != nil {
+		res.Max = lo.ToPtr[any](stats.Max)
+	}
+
+	if stats.TopValues != nil {
+		res.TopValues = lo.ToPtr(lo.Map(stats.TopValues, func(vc async_executor.ValueCount, _ int) ValueCount {
+			return ValueCount{Value: vc.Value, Count: vc.Count}
+		}))
+	}
+
+	return res
+}
+
+// ToExecution converts an execution for the given viewer (a quota key): executions are shared between
+// callers through collapsing, so the creator identity is only disclosed to the creator itself.
+func ToExecution(ex *async_executor.Execution, viewer string) *Execution {
 	if ex == nil {
 		return nil
 	}
@@ -39,9 +78,23 @@ func ToExecution(ex *async_executor.Execution) *Execution {
 	res.CreatedAt = ex.CreatedAt
 	res.Query = ex.Query
 	res.Status = ExecutionStatus(ex.Status)
+	res.Tier = &ex.Tier
+	res.CollapsedCounter = &ex.CollapsedCounter
 	res.PickedAt = ex.PickedAt
+	res.PickedBy = ex.PickedBy
+	res.BackendQueryId = lo.ToPtr(async_executor.BackendQueryId(ex.Id, ex.Attempt))
 	res.CompletedAt = ex.CompletedAt
 	res.Error = ex.Error
+	res.ExpiresAt = ex.ExpiresAt
+	res.Pinned = &ex.Pinned
+
+	if ex.Ttl != nil {
+		res.Ttl = lo.ToPtr(int64(ex.Ttl.Seconds()))
+	}
+
+	if ex.CreatedBy == viewer {
+		res.CreatedBy = &ex.CreatedBy
+	}
 
 	if ex.Progress != nil {
 		res.Progress = v1.ToProgress(ex.Progress)
@@ -53,3 +106,45 @@ func ToExecution(ex *async_executor.Execution) *Execution {
 
 	return &res
 }
+
+func ToUsage(agg *async_executor.UsageAggregate) *Usage {
+	if agg == nil {
+		return nil
+	}
+
+	return &Usage{
+		Period:      agg.Period,
+		Tier:        agg.Tier,
+		Source:      UsageSource(agg.Source),
+		Queries:     agg.Queries,
+		RowsRead:    agg.RowsRead,
+		BytesRead:   agg.BytesRead,
+		Elapsed:     agg.ElapsedMs,
+		ResultRows:  agg.ResultRows,
+		ResultBytes: agg.ResultBytes,
+	}
+}
+
+// ToExecutionEvent converts an execution event for the given viewer (a quota key): the actor is only
+// disclosed when it is the viewer, other callers, operators (with their IP) and workers being hidden.
+func ToExecutionEvent(ev *async_executor.ExecutionEvent, viewer string) ExecutionEvent {
+	var res = ExecutionEvent{
+		Id:        ev.Id,
+		CreatedAt: ev.CreatedAt,
+		Type:      ExecutionEventType(ev.Type),
+	}
+
+	if ev.Actor != nil && *ev.Actor == viewer {
+		res.Actor = ev.Actor
+	}
+
+	if ev.Status != nil {
+		res.Status = lo.ToPtr(ExecutionStatus(*ev.Status))
+	}
+
+	if ev.Data != nil {
+		res.Data = &ev.Data
+	}
+
+	return res
+}
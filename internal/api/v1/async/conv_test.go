@@ -0,0 +1,32 @@
+package async
+
+import (
+	"testing"
+
+	"github.com/agnosticeng/agp/internal/async_executor"
+	"github.com/samber/lo"
+)
+
+func TestToExecutionEventActor(t *testing.T) {
+	var cases = []struct {
+		typ   async_executor.ExecutionEventType
+		actor *string
+		want  *string
+	}{
+		{typ: async_executor.ExecutionEventCreated, actor: lo.ToPtr("viewer"), want: lo.ToPtr("viewer")},
+		{typ: async_executor.ExecutionEventCreated, actor: lo.ToPtr("other")},
+		{typ: async_executor.ExecutionEventCollapsed, actor: lo.ToPtr("other")},
+		{typ: async_executor.ExecutionEventCanceled, actor: lo.ToPtr("viewer"), want: lo.ToPtr("viewer")},
+		{typ: async_executor.ExecutionEventRequeued, actor: lo.ToPtr("admin:10.0.0.1")},
+		{typ: async_executor.ExecutionEventPicked, actor: lo.ToPtr("worker-1")},
+		{typ: async_executor.ExecutionEventPicked},
+	}
+
+	for _, c := range cases {
+		var res = ToExecutionEvent(&async_executor.ExecutionEvent{Type: c.typ, Actor: c.actor}, "viewer")
+
+		if lo.FromPtr(res.Actor) != lo.FromPtr(c.want) || (res.Actor == nil) != (c.want == nil) {
+			t.Errorf("%s by %v: got actor %v, want %v", c.typ, lo.FromPtr(c.actor), lo.FromPtr(res.Actor), lo.FromPtr(c.want))
+		}
+	}
+}
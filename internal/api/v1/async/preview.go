@@ -0,0 +1,58 @@
+package async
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	"github.com/agnosticeng/agp/internal/async_executor"
+	"github.com/agnosticeng/agp/internal/utils"
+	"github.com/samber/lo"
+)
+
+// addPreviews sets the result preview of successful executions when requested.
+func (srv *Server) addPreviews(ctx context.Context, include *Include, exs ...*Execution) error {
+	if !slices.Contains(utils.Deref(include), Preview) {
+		return nil
+	}
+
+	var ids = lo.FilterMap(exs, func(ex *Execution, _ int) (int64, bool) {
+		return ex.Id, ex.Status == ExecutionStatusSUCCEEDED
+	})
+
+	previews, err := srv.aex.GetPreviews(ctx, ids)
+
+	if err != nil {
+		return err
+	}
+
+	for _, ex := range exs {
+		if preview, found := previews[ex.Id]; found {
+			if ex.Preview, err = ToResultPreview(preview); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func ToResultPreview(preview *async_executor.ResultPreview) (*ResultPreview, error) {
+	var (
+		res  = ResultPreview{Complete: preview.Complete}
+		rows []json.RawMessage
+	)
+
+	// rows are kept raw so that numbers stay exact and keys stay in the order of the schema
+	if err := json.Unmarshal(preview.Data, &rows); err != nil {
+		return nil, err
+	}
+
+	res.Data = make([]any, len(rows))
+
+	for i, row := range rows {
+		res.Data[i] = row
+	}
+
+	return &res, nil
+}
@@ -0,0 +1,272 @@
+package async
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/agnosticeng/agp/internal/async_executor"
+	"github.com/agnosticeng/agp/internal/backend"
+)
+
+// resultResponse sends a stored result, either as is with its storage compression as the content coding,
+// or decompressed. The content coding is set before writing so that the gzip middleware passes it through.
+type resultResponse struct {
+	logger          *slog.Logger
+	body            io.Reader
+	stored          io.Closer
+	contentEncoding string
+	size            int64
+	sha256          string
+	// acceptRanges is set when the result is sent as stored, ranges are not supported otherwise
+	acceptRanges bool
+	// contentRange is set for partial responses
+	contentRange *byteRange
+	totalSize    int64
+}
+
+func (response resultResponse) VisitGetExecutionsExecutionIdResultResponse(w http.ResponseWriter) error {
+	var statusCode = http.StatusOK
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Add("Vary", "Accept-Encoding")
+
+	if len(response.contentEncoding) > 0 {
+		w.Header().Set("Content-Encoding", response.contentEncoding)
+	}
+
+	if response.size > 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(response.size, 10))
+	}
+
+	setETag(w, response.sha256)
+
+	if sum, err := hex.DecodeString(response.sha256); err == nil && len(sum) > 0 && response.contentRange == nil {
+		w.Header().Set("Digest", "sha-256="+base64.StdEncoding.EncodeToString(sum))
+	}
+
+	if response.acceptRanges {
+		w.Header().Set("Accept-Ranges", "bytes")
+	}
+
+	if response.contentRange != nil {
+		statusCode = http.StatusPartialContent
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", response.contentRange.start, response.contentRange.end, response.totalSize))
+	}
+
+	w.WriteHeader(statusCode)
+
+	// decompressors do not close the stored object reader they wrap
+	if response.stored != nil {
+		defer response.stored.Close()
+	}
+
+	if closer, ok := response.body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+
+	// the status was sent, aborting the connection is the only way to tell the client that the body is
+	// incomplete, e.g. when the stored result is corrupted
+	if _, err := io.Copy(w, response.body); err != nil {
+		if errors.Is(err, async_executor.ErrResultCorrupted) {
+			response.logger.Error(err.Error())
+		}
+
+		panic(http.ErrAbortHandler)
+	}
+
+	return nil
+}
+
+// transcodedResult sends a stored result in another format, its size and hash are unknown.
+func (srv *Server) transcodedResult(
+	ctx context.Context,
+	ex *async_executor.Execution,
+	format async_executor.ResultFormat,
+) (GetExecutionsExecutionIdResultResponseObject, error) {
+	r, err := srv.aex.GetResultReader(ctx, ex)
+
+	if err != nil {
+		return nil, err
+	}
+
+	cr, err := async_executor.Decompressor(ex.Result.StorageCompression, r)
+
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+
+	var pr, pw = io.Pipe()
+
+	go func() {
+		pw.CloseWithError(backend.Transcode(pw, cr, backend.Format(format)))
+
+		if closer, ok := cr.(io.Closer); ok {
+			closer.Close()
+		}
+	}()
+
+	return resultResponse{logger: srv.logger, body: pr, stored: r}, nil
+}
+
+type notModifiedResponse struct {
+	sha256 string
+}
+
+func (response notModifiedResponse) VisitGetExecutionsExecutionIdResultResponse(w http.ResponseWriter) error {
+	setETag(w, response.sha256)
+	w.Header().Add("Vary", "Accept-Encoding")
+	w.WriteHeader(http.StatusNotModified)
+	return nil
+}
+
+type rangeNotSatisfiableResponse struct {
+	size int64
+}
+
+func (response rangeNotSatisfiableResponse) VisitGetExecutionsExecutionIdResultResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", response.size))
+	w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+	return nil
+}
+
+func setETag(w http.ResponseWriter, sha256 string) {
+	if len(sha256) > 0 {
+		w.Header().Set("ETag", strconv.Quote(sha256))
+	}
+}
+
+// acceptsEncoding reports whether an Accept-Encoding header explicitly accepts the given content coding.
+func acceptsEncoding(header string, encoding string) bool {
+	for _, item := range strings.Split(header, ",") {
+		var coding, params, _ = strings.Cut(strings.TrimSpace(item), ";")
+
+		if !strings.EqualFold(strings.TrimSpace(coding), encoding) {
+			continue
+		}
+
+		var k, v, found = strings.Cut(strings.TrimSpace(params), "=")
+
+		if !found || !strings.EqualFold(strings.TrimSpace(k), "q") {
+			return true
+		}
+
+		q, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return err == nil && q > 0
+	}
+
+	return false
+}
+
+// matchesETag reports whether an If-None-Match header matches the ETag of a result, using weak comparison.
+func matchesETag(header string, sha256 string) bool {
+	if len(sha256) == 0 {
+		return false
+	}
+
+	for _, item := range strings.Split(header, ",") {
+		var tag = strings.TrimPrefix(strings.TrimSpace(item), "W/")
+
+		if tag == "*" || tag == strconv.Quote(sha256) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ifRangeMatches reports whether a Range applies given an If-Range header, which only matches the
+// current ETag with strong comparison.
+func ifRangeMatches(header *string, sha256 string) bool {
+	if header == nil {
+		return true
+	}
+
+	return len(sha256) > 0 && strings.TrimSpace(*header) == strconv.Quote(sha256)
+}
+
+var errUnsatisfiableRange = errors.New("unsatisfiable range")
+
+// byteRange is an inclusive range of bytes.
+type byteRange struct {
+	start int64
+	end   int64
+}
+
+func (r byteRange) length() int64 {
+	return r.end - r.start + 1
+}
+
+// parseRange parses a Range header with a single byte range; a header that is malformed or has
+// several ranges returns an error and is ignored, the whole result being sent instead.
+func parseRange(header string, size int64) (*byteRange, error) {
+	var spec, found = strings.CutPrefix(strings.TrimSpace(header), "bytes=")
+
+	if !found {
+		return nil, fmt.Errorf("unsupported range unit: %s", header)
+	}
+
+	if strings.Contains(spec, ",") {
+		return nil, fmt.Errorf("multiple ranges are not supported")
+	}
+
+	first, last, found := strings.Cut(strings.TrimSpace(spec), "-")
+
+	if !found {
+		return nil, fmt.Errorf("malformed range: %s", header)
+	}
+
+	// suffix range: the last N bytes
+	if len(first) == 0 {
+		n, err := parseRangePosition(last)
+
+		if err != nil {
+			return nil, fmt.Errorf("malformed range: %s", header)
+		}
+
+		if n == 0 || size == 0 {
+			return nil, errUnsatisfiableRange
+		}
+
+		return &byteRange{start: max(size-n, 0), end: size - 1}, nil
+	}
+
+	start, err := parseRangePosition(first)
+
+	if err != nil {
+		return nil, fmt.Errorf("malformed range: %s", header)
+	}
+
+	var end = size - 1
+
+	if len(last) > 0 {
+		end, err = parseRangePosition(last)
+
+		if err != nil || end < start {
+			return nil, fmt.Errorf("malformed range: %s", header)
+		}
+	}
+
+	if start >= size {
+		return nil, errUnsatisfiableRange
+	}
+
+	return &byteRange{start: start, end: min(end, size-1)}, nil
+}
+
+// parseRangePosition parses a byte position of a range, which only has digits.
+func parseRangePosition(s string) (int64, error) {
+	if len(s) == 0 || strings.TrimLeft(s, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid range position: %q", s)
+	}
+
+	return strconv.ParseInt(s, 10, 64)
+}
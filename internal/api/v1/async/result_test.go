@@ -0,0 +1,174 @@
+package async
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/iotest"
+
+	"github.com/agnosticeng/agp/internal/async_executor"
+	"github.com/agnosticeng/agp/internal/utils"
+)
+
+func TestResultResponseAbortsOnReadError(t *testing.T) {
+	var (
+		content = bytes.Repeat([]byte("[{\"a\":1}]"), 16*1024)
+		body    = io.MultiReader(bytes.NewReader(content), iotest.ErrReader(fmt.Errorf("%w: test", async_executor.ErrResultCorrupted)))
+		srv     = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			resultResponse{logger: slog.New(slog.NewTextHandler(io.Discard, nil)), body: body, size: int64(len(content)) + 10}.VisitGetExecutionsExecutionIdResultResponse(w)
+		}))
+	)
+
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+
+	// the connection may be closed before the buffered headers are sent
+	if err != nil {
+		return
+	}
+
+	defer resp.Body.Close()
+
+	if _, err := io.ReadAll(resp.Body); err == nil {
+		t.Fatal("expected the transfer to be aborted")
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	var cases = []struct {
+		header string
+		size   int64
+		want   *byteRange
+		err    error
+	}{
+		{header: "bytes=0-99", size: 1000, want: &byteRange{0, 99}},
+		{header: "bytes=100-", size: 1000, want: &byteRange{100, 999}},
+		{header: " bytes=100-199 ", size: 1000, want: &byteRange{100, 199}},
+		{header: "bytes=900-2000", size: 1000, want: &byteRange{900, 999}},
+		{header: "bytes=999-999", size: 1000, want: &byteRange{999, 999}},
+		{header: "bytes=-100", size: 1000, want: &byteRange{900, 999}},
+		{header: "bytes=-2000", size: 1000, want: &byteRange{0, 999}},
+		{header: "bytes=1000-", size: 1000, err: errUnsatisfiableRange},
+		{header: "bytes=1000-1100", size: 1000, err: errUnsatisfiableRange},
+		{header: "bytes=-0", size: 1000, err: errUnsatisfiableRange},
+		{header: "bytes=-10", size: 0, err: errUnsatisfiableRange},
+		{header: "bytes=0-0", size: 0, err: errUnsatisfiableRange},
+		{header: "items=0-99", size: 1000},
+		{header: "bytes=0-99,200-299", size: 1000},
+		{header: "bytes=100", size: 1000},
+		{header: "bytes=-", size: 1000},
+		{header: "bytes=--5", size: 1000},
+		{header: "bytes=+5-10", size: 1000},
+		{header: "bytes=5-+10", size: 1000},
+		{header: "bytes=a-b", size: 1000},
+		{header: "bytes=99-0", size: 1000},
+		{header: "bytes=99999999999999999999-", size: 1000},
+	}
+
+	for _, c := range cases {
+		got, err := parseRange(c.header, c.size)
+
+		switch {
+		case c.want != nil:
+			if err != nil || *got != *c.want {
+				t.Errorf("%q (size %d): got %v, %v, want %v", c.header, c.size, got, err, *c.want)
+			}
+		case c.err != nil:
+			if !errors.Is(err, c.err) {
+				t.Errorf("%q (size %d): got %v, %v, want %v", c.header, c.size, got, err, c.err)
+			}
+		default:
+			// malformed ranges are ignored rather than unsatisfiable
+			if err == nil || errors.Is(err, errUnsatisfiableRange) {
+				t.Errorf("%q (size %d): got %v, %v, want a malformed range error", c.header, c.size, got, err)
+			}
+		}
+	}
+}
+
+const testSha256 = "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+
+func TestMatchesETag(t *testing.T) {
+	var cases = []struct {
+		header string
+		sha256 string
+		want   bool
+	}{
+		{header: `"` + testSha256 + `"`, sha256: testSha256, want: true},
+		{header: `W/"` + testSha256 + `"`, sha256: testSha256, want: true},
+		{header: `"other", "` + testSha256 + `"`, sha256: testSha256, want: true},
+		{header: `*`, sha256: testSha256, want: true},
+		{header: testSha256, sha256: testSha256, want: false},
+		{header: `"other"`, sha256: testSha256, want: false},
+		{header: ``, sha256: testSha256, want: false},
+		// results without a hash have no ETag
+		{header: `*`, sha256: "", want: false},
+	}
+
+	for _, c := range cases {
+		if got := matchesETag(c.header, c.sha256); got != c.want {
+			t.Errorf("%q: got %v, want %v", c.header, got, c.want)
+		}
+	}
+}
+
+func TestIfRangeMatches(t *testing.T) {
+	var ptr = func(s string) *string { return &s }
+
+	var cases = []struct {
+		header *string
+		sha256 string
+		want   bool
+	}{
+		{header: nil, sha256: testSha256, want: true},
+		{header: nil, sha256: "", want: true},
+		{header: ptr(`"` + testSha256 + `"`), sha256: testSha256, want: true},
+		{header: ptr(` "` + testSha256 + `" `), sha256: testSha256, want: true},
+		// If-Range requires a strong comparison
+		{header: ptr(`W/"` + testSha256 + `"`), sha256: testSha256, want: false},
+		{header: ptr(`"other"`), sha256: testSha256, want: false},
+		// dates are not supported, the full result is sent
+		{header: ptr(`Wed, 21 Oct 2015 07:28:00 GMT`), sha256: testSha256, want: false},
+		{header: ptr(`""`), sha256: "", want: false},
+	}
+
+	for _, c := range cases {
+		if got := ifRangeMatches(c.header, c.sha256); got != c.want {
+			t.Errorf("%v: got %v, want %v", utils.Deref(c.header), got, c.want)
+		}
+	}
+}
+
+func TestAcceptsEncoding(t *testing.T) {
+	var cases = []struct {
+		header   string
+		encoding string
+		want     bool
+	}{
+		{header: "zstd", encoding: "zstd", want: true},
+		{header: "gzip, ZSTD", encoding: "zstd", want: true},
+		{header: "gzip;q=1.0, zstd;q=0.5", encoding: "zstd", want: true},
+		{header: "zstd; q=0.001", encoding: "zstd", want: true},
+		{header: "zstd;q=0", encoding: "zstd", want: false},
+		{header: "zstd; Q=0", encoding: "zstd", want: false},
+		{header: "zstd;q=0.000", encoding: "zstd", want: false},
+		{header: "zstd;q=invalid", encoding: "zstd", want: false},
+		// wildcards are not an explicit acceptance
+		{header: "*", encoding: "zstd", want: false},
+		{header: "gzip, deflate", encoding: "zstd", want: false},
+		{header: "", encoding: "zstd", want: false},
+		{header: "x-zstd", encoding: "zstd", want: false},
+	}
+
+	for _, c := range cases {
+		if got := acceptsEncoding(c.header, c.encoding); got != c.want {
+			t.Errorf("%q: got %v, want %v", c.header, got, c.want)
+		}
+	}
+}
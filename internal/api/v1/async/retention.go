@@ -0,0 +1,114 @@
+package async
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "github.com/agnosticeng/agp/internal/api/v1"
+	"github.com/agnosticeng/agp/internal/async_executor"
+	"github.com/agnosticeng/agp/internal/utils"
+	"github.com/samber/lo"
+)
+
+type RetentionTierConfig struct {
+	Tier string
+	// DefaultTtl applies to executions created without a ttl, the GC defaults apply when zero
+	DefaultTtl time.Duration
+	// MaxTtl bounds the ttl callers can request, unbounded when zero
+	MaxTtl   time.Duration
+	AllowPin bool
+}
+
+func (srv *Server) retentionConfig(tier string) RetentionTierConfig {
+	var conf, _ = lo.Find(srv.retention, func(t RetentionTierConfig) bool { return t.Tier == tier })
+	return conf
+}
+
+// creationTtl resolves the ttl of a new execution, it fails if the requested one is out of bounds.
+func (srv *Server) creationTtl(tier string, ttlSeconds *int64) (*time.Duration, error) {
+	var conf = srv.retentionConfig(tier)
+
+	if ttlSeconds == nil {
+		if conf.DefaultTtl > 0 {
+			return &conf.DefaultTtl, nil
+		}
+
+		return nil, nil
+	}
+
+	var ttl = time.Duration(*ttlSeconds) * time.Second
+
+	if err := conf.checkTtl(ttl); err != nil {
+		return nil, err
+	}
+
+	return &ttl, nil
+}
+
+func (conf RetentionTierConfig) checkTtl(ttl time.Duration) error {
+	if conf.MaxTtl > 0 && ttl > conf.MaxTtl {
+		return fmt.Errorf("ttl exceeds the maximum of %s for tier %s", conf.MaxTtl, conf.Tier)
+	}
+
+	return nil
+}
+
+func (srv *Server) PostExecutionsExecutionIdRetention(
+	ctx context.Context,
+	request PostExecutionsExecutionIdRetentionRequestObject,
+) (PostExecutionsExecutionIdRetentionResponseObject, error) {
+	var claims = v1.ClaimsFromContext(ctx)
+
+	if request.Body == nil || (request.Body.Ttl == nil && request.Body.Pinned == nil) {
+		return PostExecutionsExecutionIdRetention400JSONResponse{Message: "ttl or pinned must be set"}, nil
+	}
+
+	ex, err := srv.aex.GetById(ctx, request.ExecutionId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if ex == nil {
+		return PostExecutionsExecutionIdRetention404Response{}, nil
+	}
+
+	if ex.CreatedBy != claims.QuotaKey {
+		return PostExecutionsExecutionIdRetention403JSONResponse{Message: "only the creator of the execution can change its retention"}, nil
+	}
+
+	var (
+		conf = srv.retentionConfig(ex.Tier)
+		opts = async_executor.RetentionOptions{Pinned: request.Body.Pinned}
+	)
+
+	if request.Body.Ttl != nil {
+		var ttl = time.Duration(*request.Body.Ttl) * time.Second
+
+		if err := conf.checkTtl(ttl); err != nil {
+			return PostExecutionsExecutionIdRetention400JSONResponse{Message: err.Error()}, nil
+		}
+
+		opts.Ttl = &ttl
+	}
+
+	if utils.Deref(request.Body.Pinned) && !conf.AllowPin {
+		return PostExecutionsExecutionIdRetention403JSONResponse{
+			Message: fmt.Sprintf("pinning executions is not allowed for tier %s", ex.Tier),
+		}, nil
+	}
+
+	ex, err = srv.aex.UpdateRetention(ctx, ex.Id, opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the execution is not completed yet
+	if ex == nil {
+		return PostExecutionsExecutionIdRetention409Response{}, nil
+	}
+
+	return PostExecutionsExecutionIdRetention200JSONResponse(*ToExecution(ex, claims.QuotaKey)), nil
+}
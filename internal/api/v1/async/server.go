@@ -3,12 +3,15 @@ package async
 import (
 	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"time"
 
 	v1 "github.com/agnosticeng/agp/internal/api/v1"
 	"github.com/agnosticeng/agp/internal/async_executor"
+	"github.com/agnosticeng/agp/internal/presigner"
 	"github.com/agnosticeng/agp/internal/signer"
 	"github.com/agnosticeng/agp/internal/utils"
 	"github.com/samber/lo"
@@ -17,43 +20,96 @@ import (
 )
 
 type Server struct {
-	logger *slog.Logger
-	signer signer.Signer
-	aex    *async_executor.AsyncExecutor
+	logger    *slog.Logger
+	signer    signer.Signer
+	presign   presigner.Presigner
+	aex       *async_executor.AsyncExecutor
+	retention []RetentionTierConfig
 }
 
+// NewServer creates an async API server; results are always proxied when presign is nil.
 func NewServer(
 	ctx context.Context,
 	signer signer.Signer,
+	presign presigner.Presigner,
 	aex *async_executor.AsyncExecutor,
+	retention []RetentionTierConfig,
 ) *Server {
 	return &Server{
-		logger: slogctx.FromCtx(ctx),
-		signer: signer,
-		aex:    aex,
+		logger:    slogctx.FromCtx(ctx),
+		signer:    signer,
+		presign:   presign,
+		aex:       aex,
+		retention: retention,
 	}
 }
 
+func (srv *Server) GetExecutions(
+	ctx context.Context,
+	request GetExecutionsRequestObject,
+) (GetExecutionsResponseObject, error) {
+	var (
+		claims = v1.ClaimsFromContext(ctx)
+		opts   = async_executor.ListExecutionsOptions{
+			ExecutionFilter: async_executor.ExecutionFilter{
+				CreatedBy: claims.QuotaKey,
+				Statuses: lo.Map(
+					utils.Deref(request.Params.Status),
+					func(st ExecutionStatus, _ int) async_executor.Status {
+						return async_executor.Status(st)
+					},
+				),
+				Tiers:         utils.Deref(request.Params.Tier),
+				QueryIdPrefix: utils.Deref(request.Params.QueryIdPrefix),
+				CreatedAfter:  request.Params.CreatedAfter,
+				CreatedBefore: request.Params.CreatedBefore,
+			},
+			Limit:      int(utils.DerefOr(request.Params.Limit, 20)),
+			TotalCount: utils.DerefOr(request.Params.TotalCount, false),
+		}
+	)
+
+	if request.Params.Cursor != nil {
+		cursor, err := async_executor.DecodeExecutionCursor(*request.Params.Cursor)
+
+		if err != nil {
+			return GetExecutions400JSONResponse{Message: err.Error()}, nil
+		}
+
+		opts.After = cursor
+	}
+
+	page, err := srv.aex.ListExecutions(ctx, opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var res = GetExecutions200JSONResponse{
+		Items:      lo.Map(page.Executions, func(ex *async_executor.Execution, _ int) Execution { return *ToExecution(ex, claims.QuotaKey) }),
+		TotalCount: page.TotalCount,
+	}
+
+	if page.Next != nil {
+		res.NextCursor = lo.ToPtr(page.Next.Encode())
+	}
+
+	return res, nil
+}
+
 func (srv *Server) PostExecutions(
 	ctx context.Context,
 	request PostExecutionsRequestObject,
 ) (PostExecutionsResponseObject, error) {
 	var (
-		claims  = v1.ClaimsFromContext(ctx)
-		sql     string
-		secrets = make(map[string]string)
+		claims       = v1.ClaimsFromContext(ctx)
+		sql, secrets = requestQuery(request)
 	)
 
-	if request.TextBody != nil {
-		sql = *request.TextBody
-	} else {
-		sql = request.JSONBody.Sql
+	ttl, err := srv.creationTtl(claims.Tier, request.Params.Ttl)
 
-		if request.JSONBody.Secrets != nil {
-			for _, secret := range *request.JSONBody.Secrets {
-				secrets[secret.Key] = secret.Value
-			}
-		}
+	if err != nil {
+		return PostExecutions400JSONResponse{Message: err.Error()}, nil
 	}
 
 	ex, err := srv.aex.Create(
@@ -64,6 +120,7 @@ func (srv *Server) PostExecutions(
 			QueryId: utils.Deref(request.Params.QueryId),
 			Tier:    utils.Deref(&claims.Tier),
 			Secrets: secrets,
+			Ttl:     ttl,
 		},
 	)
 
@@ -71,13 +128,35 @@ func (srv *Server) PostExecutions(
 		return nil, err
 	}
 
-	return PostExecutions201JSONResponse(*ToExecution(ex)), nil
+	return PostExecutions201JSONResponse(*ToExecution(ex, claims.QuotaKey)), nil
+}
+
+func requestQuery(request PostExecutionsRequestObject) (string, map[string]string) {
+	var secrets = make(map[string]string)
+
+	if request.TextBody != nil {
+		return *request.TextBody, secrets
+	}
+
+	if request.JSONBody == nil {
+		return "", secrets
+	}
+
+	if request.JSONBody.Secrets != nil {
+		for _, secret := range *request.JSONBody.Secrets {
+			secrets[secret.Key] = secret.Value
+		}
+	}
+
+	return request.JSONBody.Sql, secrets
 }
 
 func (srv *Server) GetExecutionsExecutionId(
 	ctx context.Context,
 	request GetExecutionsExecutionIdRequestObject,
 ) (GetExecutionsExecutionIdResponseObject, error) {
+	var claims = v1.ClaimsFromContext(ctx)
+
 	ex, err := srv.aex.GetById(ctx, request.ExecutionId)
 
 	if err != nil {
@@ -89,7 +168,40 @@ func (srv *Server) GetExecutionsExecutionId(
 
 	}
 
-	return GetExecutionsExecutionId200JSONResponse(*ToExecution(ex)), nil
+	var res = ToExecution(ex, claims.QuotaKey)
+
+	if err := srv.addPreviews(ctx, request.Params.Include, res); err != nil {
+		return nil, err
+	}
+
+	return GetExecutionsExecutionId200JSONResponse(*res), nil
+}
+
+func (srv *Server) GetExecutionsExecutionIdEvents(
+	ctx context.Context,
+	request GetExecutionsExecutionIdEventsRequestObject,
+) (GetExecutionsExecutionIdEventsResponseObject, error) {
+	var claims = v1.ClaimsFromContext(ctx)
+
+	ex, err := srv.aex.GetById(ctx, request.ExecutionId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if ex == nil {
+		return GetExecutionsExecutionIdEvents404Response{}, nil
+	}
+
+	evs, err := srv.aex.ListEvents(ctx, ex.Id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return GetExecutionsExecutionIdEvents200JSONResponse(lo.Map(evs, func(ev *async_executor.ExecutionEvent, _ int) ExecutionEvent {
+		return ToExecutionEvent(ev, claims.QuotaKey)
+	})), nil
 }
 
 func (srv *Server) GetExecutionsExecutionIdResult(
@@ -116,19 +228,138 @@ func (srv *Server) GetExecutionsExecutionIdResult(
 		return GetExecutionsExecutionIdResult404Response{}, nil
 	}
 
+	// the execution has no stored result
+	if ex.Result == nil || len(ex.Result.StoragePath) == 0 {
+		return GetExecutionsExecutionIdResult404Response{}, nil
+	}
+
+	var (
+		storageFormat = lo.CoalesceOrEmpty(ex.Result.StorageFormat, async_executor.ResultFormatJSON)
+		format        = async_executor.ResultFormat(utils.DerefOr(request.Params.Format, ResultFormat(storageFormat)))
+		encoding      = async_executor.ContentEncoding(ex.Result.StorageCompression)
+		// stored bytes are sent as is when in the requested format, and uncompressed or when the client
+		// accepts their compression
+		asStored = format == storageFormat &&
+			(len(encoding) == 0 || acceptsEncoding(utils.Deref(request.Params.AcceptEncoding), encoding))
+		sha256 = ex.Result.ContentSha256
+	)
+
+	if format != storageFormat {
+		return srv.transcodedResult(ctx, ex, format)
+	}
+
+	if asStored {
+		sha256 = ex.Result.StorageSha256
+	}
+
+	if matchesETag(utils.Deref(request.Params.IfNoneMatch), sha256) {
+		return notModifiedResponse{sha256: sha256}, nil
+	}
+
+	if asStored && srv.presign != nil {
+		u, err := srv.aex.ResultURL(ex)
+
+		if err != nil {
+			return nil, err
+		}
+
+		location, ok, err := srv.presign(u, presigner.PresignOptions{
+			// the presigned URL must not outlive the signed one
+			Expiration:      time.Until(time.Unix(request.Params.Expiration, 0)),
+			ContentType:     "application/octet-stream",
+			ContentEncoding: encoding,
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		// object stores that cannot presign URLs fall back to proxying
+		if ok {
+			return GetExecutionsExecutionIdResult307Response{
+				Headers: GetExecutionsExecutionIdResult307ResponseHeaders{Location: location},
+			}, nil
+		}
+	}
+
+	if asStored && ex.Result.StorageSize > 0 && request.Params.Range != nil && ifRangeMatches(request.Params.IfRange, sha256) {
+		rng, err := parseRange(*request.Params.Range, ex.Result.StorageSize)
+
+		if errors.Is(err, errUnsatisfiableRange) {
+			return rangeNotSatisfiableResponse{size: ex.Result.StorageSize}, nil
+		}
+
+		// other invalid ranges are ignored
+		if err == nil {
+			ra, err := srv.aex.GetResultReaderAt(ctx, ex)
+
+			if err != nil {
+				return nil, err
+			}
+
+			return resultResponse{
+				logger:          srv.logger,
+				body:            io.NewSectionReader(ra, rng.start, rng.length()),
+				stored:          ra,
+				contentEncoding: encoding,
+				size:            rng.length(),
+				sha256:          sha256,
+				acceptRanges:    true,
+				contentRange:    rng,
+				totalSize:       ex.Result.StorageSize,
+			}, nil
+		}
+	}
+
 	r, err := srv.aex.GetResultReader(ctx, ex)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if asStored {
+		return resultResponse{
+			logger:          srv.logger,
+			body:            r,
+			contentEncoding: encoding,
+			size:            ex.Result.StorageSize,
+			sha256:          sha256,
+			acceptRanges:    ex.Result.StorageSize > 0,
+		}, nil
+	}
+
 	cr, err := async_executor.Decompressor(ex.Result.StorageCompression, r)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return GetExecutionsExecutionIdResult200ApplicationoctetStreamResponse{Body: cr}, nil
+	return resultResponse{
+		logger: srv.logger,
+		body:   cr,
+		stored: r,
+		size:   ex.Result.ContentSize,
+		sha256: sha256,
+	}, nil
+}
+
+func (srv *Server) GetUsage(ctx context.Context, request GetUsageRequestObject) (GetUsageResponseObject, error) {
+	var claims = v1.ClaimsFromContext(ctx)
+
+	aggs, err := srv.aex.AggregateUsage(ctx, async_executor.AggregateUsageOptions{
+		QuotaKey:    claims.QuotaKey,
+		Granularity: async_executor.UsageGranularity(utils.Deref(request.Params.Granularity)),
+		From:        utils.Deref(request.Params.From),
+		To:          utils.Deref(request.Params.To),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return GetUsage200JSONResponse(lo.Map(aggs, func(agg *async_executor.UsageAggregate, _ int) Usage {
+		return *ToUsage(agg)
+	})), nil
 }
 
 func (srv *Server) PostSearch(ctx context.Context, request PostSearchRequestObject) (PostSearchResponseObject, error) {
@@ -136,6 +367,8 @@ func (srv *Server) PostSearch(ctx context.Context, request PostSearchRequestObje
 		return PostSearch200JSONResponse{}, nil
 	}
 
+	var claims = v1.ClaimsFromContext(ctx)
+
 	var p = pool.
 		NewWithResults[[]Execution]().
 		WithErrors().
@@ -164,7 +397,7 @@ func (srv *Server) PostSearch(ctx context.Context, request PostSearchRequestObje
 			}
 
 			return lo.Map(exs, func(ex *async_executor.Execution, _ int) Execution {
-				return *ToExecution(ex)
+				return *ToExecution(ex, claims.QuotaKey)
 			}), nil
 		})
 	}
@@ -175,5 +408,17 @@ func (srv *Server) PostSearch(ctx context.Context, request PostSearchRequestObje
 		return nil, err
 	}
 
+	var exs []*Execution
+
+	for i := range res {
+		for j := range res[i] {
+			exs = append(exs, &res[i][j])
+		}
+	}
+
+	if err := srv.addPreviews(ctx, request.Params.Include, exs...); err != nil {
+		return nil, err
+	}
+
 	return PostSearch200JSONResponse(res), nil
 }
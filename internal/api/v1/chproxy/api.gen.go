@@ -220,12 +220,12 @@ func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.H
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/3xRu27jMBD8FWNq2lJynbrcNZfOQNIFxoFHrWPiJC6zXNoWBP77gRSSMioEcPYxMzsr",
-	"HM+RAwVNGFYkclm8Li/uQjM16IWckLZiAzHgL1khgYEusb4vqhGlFAMfzlxb1etUK0/vgZN6tzsK35fd",
-	"r8m7f5wT7X6/vh53T8dnGFxJkueAAQ+H/tCjGHCkYKPHgB+H/vAIg2j10uR09Rc5NUUcSax6Ds8jBhwr",
-	"WlvFzqQkCcPbCl83f2SSBQbBNgMjnW2e9M+ZZbZ1plmzTflmKan48I5STgZCH5mS/uRxqR2Og1Jo/DbG",
-	"ybumoLvvb7fbvm7cZ5koOB5pxLAWA6W7dnGyVcr6DVfZuLzUQZVMDUiRQ9qyeOz7bxSwU9J9UiE7V+Lt",
-	"M1+htnN8xvl2Kmat7hLJ9fNWWSYM6K4PnbvEmhjKqfwPAAD//yNLSzUkAgAA",
+	"H4sIAAAAAAAC/3xRy27bMBD8FWPOtKWkN93SXpqbgeQWGAVLrWOiEpdZLm0LAv+9IIXkVFQHAZx9zMzO",
+	"Csdz5EBBE4YViVwWr8uLu9BMDXohJ6St2EAM+E1WSGCgS6zvi2pEKcXAhzPXVvU61crTe+Ck3u2Owvdl",
+	"92Py7g/nRLufr6/H3dPxGQZXkuQ5YMDDoT/0KAYcKdjoMeDboT88wiBavTQ5Xf1FTk0RRxKrnsPziAHH",
+	"itZWsTMpScLwtsLXzR+ZZIFBsM3ASGebJ/11ZpltnWnWbFO+WUoqPryjlJOB0EempN95XGqH46AUGr+N",
+	"cfKuKeju+9vttq8b91kmCo5HGjGsxUDprl2crA9fZ/wnV9m4vNRBlUwNSJFD2rJ47Pv/KGCnpPukQnau",
+	"xNtnvkJt5/iM8+1UzFrdJZLr562yTBjQXR86d4k1MZRT+TsAI0tLNSQCAAA=",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -2,14 +2,22 @@ package chproxy
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	v1 "github.com/agnosticeng/agp/internal/api/v1"
+	"github.com/agnosticeng/agp/internal/async_executor"
+	"github.com/agnosticeng/agp/internal/audit"
 	"github.com/agnosticeng/agp/internal/utils"
+	"github.com/google/uuid"
 	"github.com/samber/lo"
 	slogctx "github.com/veqryn/slog-context"
 )
@@ -19,6 +27,16 @@ var forwardHeaderPrefixes = []string{
 	"x-clickhouse-",
 }
 
+const killQueryTimeout = 10 * time.Second
+
+// exceptionTailSize is the number of trailing response bytes searched for an exception ClickHouse
+// raised after the response status was sent.
+const exceptionTailSize = 4096
+
+// exceptionPattern matches the message ClickHouse appends to the response body of a query that
+// fails while its result is being sent.
+var exceptionPattern = regexp.MustCompile(`Code: \d+\. DB::Exception: [^\n]*`)
+
 type BackendTier struct {
 	Tier    string
 	Backend string
@@ -28,11 +46,14 @@ type Server struct {
 	logger *slog.Logger
 	bkds   []BackendTier
 	client *http.Client
+	aex    *async_executor.AsyncExecutor
 }
 
+// NewServer creates a ClickHouse proxy server; usage is only recorded when aex is not nil.
 func NewServer(
 	ctx context.Context,
 	bkds []BackendTier,
+	aex *async_executor.AsyncExecutor,
 ) (*Server, error) {
 	if len(bkds) == 0 {
 		return nil, fmt.Errorf("at least one backend tier must be specified")
@@ -42,6 +63,7 @@ func NewServer(
 		logger: slogctx.FromCtx(ctx),
 		bkds:   bkds,
 		client: &http.Client{},
+		aex:    aex,
 	}, nil
 }
 
@@ -57,18 +79,30 @@ func (srv *Server) Post(w http.ResponseWriter, r *http.Request, params PostParam
 		return
 	}
 
-	upstreamReq, err := http.NewRequest("POST", bkd.Backend, r.Body)
+	upstreamReq, err := http.NewRequestWithContext(r.Context(), "POST", bkd.Backend, r.Body)
 
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	var queryId = "agp-chproxy-" + uuid.Must(uuid.NewV7()).String()
+
+	// a client disconnection only closes the upstream connection, the query must be killed explicitly
+	defer func() {
+		if r.Context().Err() != nil {
+			srv.killQuery(bkd.Backend, queryId)
+		}
+	}()
+
 	var upstreamParams = upstreamReq.URL.Query()
+	upstreamParams.Set("query_id", queryId)
 	upstreamParams.Set("quota_key", claims.QuotaKey)
 	upstreamParams.Set("default_format", utils.DerefOr(params.DefaultFormat, "TabSeparated"))
 	upstreamReq.URL.RawQuery = upstreamParams.Encode()
 
+	var t0 = time.Now()
+
 	upstreamResp, err := srv.client.Do(upstreamReq)
 
 	if err != nil {
@@ -90,7 +124,156 @@ func (srv *Server) Post(w http.ResponseWriter, r *http.Request, params PostParam
 	}
 
 	w.WriteHeader(upstreamResp.StatusCode)
-	io.Copy(w, upstreamResp.Body)
+
+	var (
+		tail       = &tailWriter{max: exceptionTailSize}
+		n, copyErr = io.Copy(io.MultiWriter(w, tail), upstreamResp.Body)
+	)
+
+	audit.ReportQuery(r.Context(), upstreamError(upstreamResp, tail.buf, copyErr))
+	srv.recordUsage(claims, t0, upstreamResp.Header.Get("X-ClickHouse-Summary"), n)
+}
+
+// Ping runs a trivial query on the upstream of the given tier.
+func (srv *Server) Ping(ctx context.Context, tier string) error {
+	var bkd, found = lo.Find(srv.bkds, func(v BackendTier) bool { return v.Tier == tier })
+
+	if !found {
+		return fmt.Errorf("no backend found for tier: %s", tier)
+	}
+
+	_, err := srv.execute(ctx, bkd.Backend, "SELECT 1")
+	return err
+}
+
+func (srv *Server) killQuery(backend string, queryId string) {
+	var (
+		logger      = srv.logger.With("query_id", queryId)
+		ctx, cancel = context.WithTimeout(context.Background(), killQueryTimeout)
+	)
+
+	defer cancel()
+
+	if _, err := srv.execute(ctx, backend, fmt.Sprintf("KILL QUERY WHERE query_id = '%s' SYNC", queryId)); err != nil {
+		logger.Error("failed to kill query", "error", err.Error())
+		return
+	}
+
+	running, err := srv.execute(ctx, backend, fmt.Sprintf("SELECT count() FROM system.processes WHERE query_id = '%s' FORMAT TabSeparated", queryId))
+
+	if err != nil {
+		logger.Error("failed to confirm query was killed", "error", err.Error())
+		return
+	}
+
+	if strings.TrimSpace(running) != "0" {
+		logger.Error("query is still running after being killed")
+		return
+	}
+
+	logger.Info("query killed")
+}
+
+// execute runs a query on the upstream and returns its output.
+func (srv *Server) execute(ctx context.Context, backend string, query string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", backend, strings.NewReader(query))
+
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := srv.client.Do(req)
+
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("upstream returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return string(body), nil
+}
+
+// upstreamError tells whether a proxied query failed, the response status being 200 when the
+// failure happens once results are streamed.
+func upstreamError(resp *http.Response, tail []byte, copyErr error) error {
+	switch {
+	case copyErr != nil:
+		return copyErr
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("upstream returned status %d", resp.StatusCode)
+	case len(resp.Header.Get("X-ClickHouse-Exception-Code")) > 0:
+		return fmt.Errorf("upstream returned exception code %s", resp.Header.Get("X-ClickHouse-Exception-Code"))
+	}
+
+	if m := exceptionPattern.Find(tail); m != nil {
+		return errors.New(string(m))
+	}
+
+	return nil
+}
+
+// tailWriter keeps the last max bytes written to it.
+type tailWriter struct {
+	max int
+	buf []byte
+}
+
+func (w *tailWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+
+	if len(w.buf) > w.max {
+		w.buf = append(w.buf[:0], w.buf[len(w.buf)-w.max:]...)
+	}
+
+	return len(p), nil
+}
+
+// clickhouseSummary is the content of the X-ClickHouse-Summary header, ClickHouse encodes numbers as strings.
+type clickhouseSummary struct {
+	ReadRows   string `json:"read_rows"`
+	ReadBytes  string `json:"read_bytes"`
+	ResultRows string `json:"result_rows"`
+}
+
+func (srv *Server) recordUsage(claims *v1.Claims, t0 time.Time, summaryHeader string, resultBytes int64) {
+	if srv.aex == nil {
+		return
+	}
+
+	var (
+		summary clickhouseSummary
+		usage   = async_executor.Usage{
+			QuotaKey:    claims.QuotaKey,
+			Tier:        claims.Tier,
+			Source:      async_executor.UsageSourceChProxy,
+			Elapsed:     time.Since(t0),
+			ResultBytes: resultBytes,
+		}
+	)
+
+	if len(summaryHeader) > 0 {
+		if err := json.Unmarshal([]byte(summaryHeader), &summary); err != nil {
+			srv.logger.Warn("failed to parse ClickHouse summary header", "error", err.Error())
+		}
+	}
+
+	usage.RowsRead, _ = strconv.ParseInt(summary.ReadRows, 10, 64)
+	usage.BytesRead, _ = strconv.ParseInt(summary.ReadBytes, 10, 64)
+	usage.ResultRows, _ = strconv.ParseInt(summary.ResultRows, 10, 64)
+
+	if err := srv.aex.RecordUsage(context.Background(), usage); err != nil {
+		srv.logger.Error(err.Error())
+	}
 }
 
 func httpError(logger *slog.Logger, w http.ResponseWriter, err error, statusCode int) {
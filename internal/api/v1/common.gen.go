@@ -21,6 +21,17 @@ type Column struct {
 	Type string `json:"type"`
 }
 
+// Error defines model for Error.
+type Error struct {
+	// Message Why the request was rejected.
+	Message string `json:"message"`
+}
+
+// MetaEvent defines model for MetaEvent.
+type MetaEvent struct {
+	Meta *[]Column `json:"meta,omitempty"`
+}
+
 // Progress defines model for Progress.
 type Progress struct {
 	Bytes     *int64 `json:"bytes,omitempty"`
@@ -36,17 +47,31 @@ type ProgressEvent struct {
 
 // Result defines model for Result.
 type Result struct {
-	Data *[]map[string]interface{} `json:"data,omitempty"`
-	Meta *[]Column                 `json:"meta,omitempty"`
-	Rows *int64                    `json:"rows,omitempty"`
+	// Data Rows as objects keyed by column name in the order of meta (JSON format), or as arrays of values (JSON_COMPACT format).
+	Data *[]interface{} `json:"data,omitempty"`
+	Meta *[]Column      `json:"meta,omitempty"`
+	Rows *int64         `json:"rows,omitempty"`
 }
 
 // ResultEvent defines model for ResultEvent.
 type ResultEvent struct {
-	Data  *[]map[string]interface{} `json:"data,omitempty"`
-	Error *string                   `json:"error,omitempty"`
-	Meta  *[]Column                 `json:"meta,omitempty"`
-	Rows  *int64                    `json:"rows,omitempty"`
+	// Data Rows as objects keyed by column name in the order of meta (JSON format), or as arrays of values (JSON_COMPACT format).
+	Data  *[]interface{} `json:"data,omitempty"`
+	Error *string        `json:"error,omitempty"`
+	Meta  *[]Column      `json:"meta,omitempty"`
+	Rows  *int64         `json:"rows,omitempty"`
+}
+
+// RowsEvent defines model for RowsEvent.
+type RowsEvent struct {
+	// Data A batch of rows, in the requested format.
+	Data *[]interface{} `json:"data,omitempty"`
+}
+
+// Secret defines model for Secret.
+type Secret struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
 }
 
 // Authorization defines model for Authorization.
@@ -64,13 +89,16 @@ type Tier = string
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/6STz44TMQzG38VwHKkcEIfcEOLEgeXPbbVauR23NSRx6nhAw2reHSUz2121aVmJW2N/",
-	"/tn+6nmAjYQkkaJlcA+QUDGQkdbX+8H2ovwHjSWWAEdwsCfsSaGDiIHAnag6yJs9BSxyG1MRZFOOO5im",
-	"Dr4MYviJxiPsMJCOT6xDyd//pPEfnG+mhOESJc/ZBmIt4gljZXxn0ksE47rh5RGmx2Q16oP4IVSHkkoi",
-	"NaYan2ln1d0SaG2mdBhYqQd3O5cv4rvHIpD1D9pYodyo7JRyPm+8Hm3+sRUNaOCAo717C0cIR6MdaaGQ",
-	"x5RLw4Y6cOQwBHBvWpUqv1/axMTQ37+4YLqy7cdfFO185fTMjNdKW3DwavV03avl71odTWs2+Up58A16",
-	"j1bvgI1CDWDfczl49DfPhS3mEkBVHMs70Anr2rjLaTUw/2XmvOfRSvT+8xbc7fVZFnOm7tQdUhVt3/NJ",
-	"57v66XDcCrg4eN+BJIqYGBxABwltn+fM9DcAAP//p/arpZwEAAA=",
+	"H4sIAAAAAAAC/6xVQW/bPAz9KwK/77ABxrrDsINvRdHLhq5dW2CHoigYm4m1WpJL0Q28Iv99kGQnQ60k",
+	"HbBbQj0+Uo9P9AtUznTOkhUP5Qt0yGhIiOO/014ax/oXinY2BLSFEhrCmhgKsGgIyleoAnzVkMEAl6EL",
+	"AC+s7Qo2mwK+907wKw1bsqeeeNhxPYXzh0cajvDcCBOafSw+nWYoFs61hDZy3GrifQyiiXP52xY202EU",
+	"6sy1vYkKdew6YtEU44ltll2MgdzNmJ56zVRDeZfSR/D9lARu8ZMqCSznzI7nVQ15j6vIX5OvWHdpfvCj",
+	"GZQ0pEIN8qLW6BVTYKP6AxRHuploc51ckOD5M1nJdSNRQS1kYuB/piWU8N/Jznono5Yno5BbhQCZcYDN",
+	"LrCrecVuxeT9vORikPRj6digQAnayudPuxtqK7QiDizUYuepzqKNttr0BsqPuUx267cWESfYPrw54dBt",
+	"96jc/SHGIYG3omWLXJPv2wx7jYJzO127tVfoVcr36pEGqtViUFWcogr2VdpGyzmuiZVbqmAH9e7LzeU3",
+	"lXR4XyjHgSaO2gfMM7Y9+YR6OLu8uDo9u53QwaeTlWY2Kf6N2/5itvtV3A4K2/ZyCeXd4V5SEmyK19rT",
+	"9Mjnr/NV5ftQ2633WSQ/xFO1QKmaIHu4dDENbNwRVI/CH9Q9p8INVUyZNh7T9p+txDj04zsxfRsSeL6J",
+	"AlrbpYPS9m1bgOvIYqehBCigQ2l8Otn8HgD94lBh+QYAAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
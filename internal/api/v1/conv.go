@@ -4,7 +4,7 @@ import (
 	"github.com/agnosticeng/agp/internal/backend"
 )
 
-func ToResult(bkdres *backend.Result) *Result {
+func ToResult(bkdres *backend.Result, format backend.Format) *Result {
 	if bkdres == nil {
 		return nil
 	}
@@ -20,8 +20,10 @@ func ToResult(bkdres *backend.Result) *Result {
 		meta = append(meta, Column(column))
 	}
 
+	var data = bkdres.FormatRows(format)
+
 	res.Meta = &meta
-	res.Data = &bkdres.Data
+	res.Data = &data
 	return &res
 }
 
@@ -45,7 +47,7 @@ func ToProgress(bkdprog *backend.Progress) *Progress {
 	}
 }
 
-func ToResultEvent(bkdres *backend.Result, err error) *ResultEvent {
+func ToResultEvent(bkdres *backend.Result, err error, format backend.Format) *ResultEvent {
 	if bkdres == nil && err == nil {
 		return nil
 	}
@@ -55,7 +57,7 @@ func ToResultEvent(bkdres *backend.Result, err error) *ResultEvent {
 		return &ResultEvent{Error: &str}
 	}
 
-	var res = ToResult(bkdres)
+	var res = ToResult(bkdres, format)
 
 	return &ResultEvent{
 		Rows: res.Rows,
@@ -28,14 +28,60 @@ const (
 	SecretScopes = "Secret.Scopes"
 )
 
+// Defines values for PostRunParamsFormat.
+const (
+	JSON        PostRunParamsFormat = "JSON"
+	JSONCOMPACT PostRunParamsFormat = "JSON_COMPACT"
+)
+
+// PromotedExecution defines model for PromotedExecution.
+type PromotedExecution struct {
+	// ExecutionId Id of the async execution running the query, see /v1/async/executions/{execution_id}.
+	ExecutionId int64 `json:"execution_id"`
+}
+
+// Query defines model for Query.
+type Query struct {
+	// Parameters Query parameters by name, e.g. {id:UInt64} in ClickHouse; strings, numbers, booleans, null, arrays and objects (maps) are rendered as ClickHouse literals, numbers keeping their exact digits.
+	Parameters *map[string]json.RawMessage `json:"parameters,omitempty"`
+
+	// Secrets Parameters that are not logged nor audited.
+	Secrets *[]externalRef0.Secret `json:"secrets,omitempty"`
+
+	// Settings Query settings, restricted to the ones allowed by the server.
+	Settings *map[string]json.RawMessage `json:"settings,omitempty"`
+	Sql      string                      `json:"sql"`
+
+	// Timeout Timeout in seconds, bounded by the server maximum.
+	Timeout *int `json:"timeout,omitempty"`
+}
+
 // PostRunTextBody defines parameters for PostRun.
 type PostRunTextBody = string
 
 // PostRunParams defines parameters for PostRun.
 type PostRunParams struct {
 	Stream *externalRef0.Stream `form:"stream,omitempty" json:"stream,omitempty"`
+
+	// Format JSON renders rows as objects, JSON_COMPACT as arrays of values in the order of meta.
+	Format *PostRunParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// BatchSize Maximum number of rows of the rows events of streams.
+	BatchSize *int `form:"batch_size,omitempty" json:"batch_size,omitempty"`
+
+	// PromoteAfter Seconds after which a query still running is canceled and created as an async execution instead, answered with a 202; the execution runs the query again from the start, with the same timeout. Only for JSON responses, queries without settings and tiers that allow it.
+	PromoteAfter *int `form:"promote_after,omitempty" json:"promote_after,omitempty"`
+
+	// Accept Selects the response format, application/json (default), text/event-stream, application/x-ndjson, text/csv or application/vnd.apache.arrow.stream; all but application/json are streamed as rows are read.
+	Accept *string `json:"Accept,omitempty"`
 }
 
+// PostRunParamsFormat defines parameters for PostRun.
+type PostRunParamsFormat string
+
+// PostRunJSONRequestBody defines body for PostRun for application/json ContentType.
+type PostRunJSONRequestBody = Query
+
 // PostRunTextRequestBody defines body for PostRun for text/plain ContentType.
 type PostRunTextRequestBody = PostRunTextBody
 
@@ -77,6 +123,51 @@ func (siw *ServerInterfaceWrapper) PostRun(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "batch_size" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "batch_size", r.URL.Query(), &params.BatchSize)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "batch_size", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "promote_after" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "promote_after", r.URL.Query(), &params.PromoteAfter)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "promote_after", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Accept" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Accept")]; found {
+		var Accept string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Accept", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Accept", valueList[0], &Accept, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Accept", Err: err})
+			return
+		}
+
+		params.Accept = &Accept
+
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		siw.Handler.PostRun(w, r, params)
 	}))
@@ -214,8 +305,9 @@ func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.H
 }
 
 type PostRunRequestObject struct {
-	Params PostRunParams
-	Body   *PostRunTextRequestBody
+	Params   PostRunParams
+	JSONBody *PostRunJSONRequestBody
+	TextBody *PostRunTextRequestBody
 }
 
 type PostRunResponseObject interface {
@@ -231,6 +323,63 @@ func (response PostRun200JSONResponse) VisitPostRunResponse(w http.ResponseWrite
 	return json.NewEncoder(w).Encode(response)
 }
 
+type PostRun200ApplicationvndApacheArrowStreamResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response PostRun200ApplicationvndApacheArrowStreamResponse) VisitPostRunResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/vnd.apache.arrow.stream")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type PostRun200ApplicationxNdjsonResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response PostRun200ApplicationxNdjsonResponse) VisitPostRunResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type PostRun200TextcsvResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response PostRun200TextcsvResponse) VisitPostRunResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/csv")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
 type PostRun200TexteventStreamResponse struct {
 	Body          io.Reader
 	ContentLength int64
@@ -250,6 +399,46 @@ func (response PostRun200TexteventStreamResponse) VisitPostRunResponse(w http.Re
 	return err
 }
 
+type PostRun202ResponseHeaders struct {
+	Location string
+}
+
+type PostRun202JSONResponse struct {
+	Body    PromotedExecution
+	Headers PostRun202ResponseHeaders
+}
+
+func (response PostRun202JSONResponse) VisitPostRunResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprint(response.Headers.Location))
+	w.WriteHeader(202)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type PostRun400JSONResponse externalRef0.Error
+
+func (response PostRun400JSONResponse) VisitPostRunResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostRun429ResponseHeaders struct {
+	RetryAfter int
+}
+
+type PostRun429Response struct {
+	Headers PostRun429ResponseHeaders
+}
+
+func (response PostRun429Response) VisitPostRunResponse(w http.ResponseWriter) error {
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.WriteHeader(429)
+	return nil
+}
+
 // StrictServerInterface represents all server handlers.
 type StrictServerInterface interface {
 
@@ -291,14 +480,24 @@ func (sh *strictHandler) PostRun(w http.ResponseWriter, r *http.Request, params
 	var request PostRunRequestObject
 
 	request.Params = params
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
 
-	data, err := io.ReadAll(r.Body)
-	if err != nil {
-		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't read body: %w", err))
-		return
+		var body PostRunJSONRequestBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+			return
+		}
+		request.JSONBody = &body
+	}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "text/plain") {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't read body: %w", err))
+			return
+		}
+		body := PostRunTextRequestBody(data)
+		request.TextBody = &body
 	}
-	body := PostRunTextRequestBody(data)
-	request.Body = &body
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
 		return sh.ssi.PostRun(ctx, request.(PostRunRequestObject))
@@ -323,16 +522,32 @@ func (sh *strictHandler) PostRun(w http.ResponseWriter, r *http.Request, params
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/6xUT2/bPgz9KgV/v6MXu92wg2/dsENPC5pjERSqzaQqJFGl6KxG4e8+SHbi1vE2D1hO",
-	"sUi+Rz7+eYWKrCeHTgKUr+AVK4uCnL4qspbc/UYYlY0P2kEJzw1yCxk4ZRFKCL01g1A9olXRTVofLQ9E",
-	"BpWDruuO1rewX8k01iVWJo8sGpO5xz2hBGHt9tBlw8OZocuA8bnRjDWUd3344Lw9BgE9PGElEWVgXzPt",
-	"GUM4539opf+zI7ZKoATt5PMnOGFpJ7hHjmBolA+Rd8bbaqdtY6Es5iKZfiwlERJl7hcHdH8u+tsBnZxX",
-	"7t9o8j/jDkr4Lx8HJB96mE8l/B3lLYbGzHDVStKsaEGbHlRda9HklFm/dZyDHh4Us2rjt8UJ1oLkh+mb",
-	"QfsXQvdVn2RWxnzfQXm3KLNBsS6bSobMxPMLMMljmzYOq4a1tJsI3yNssGJMGSXOtKSoGHms7lHE9xur",
-	"3Y4SmxYTLdd7R0F0dbFmemkvNq2rLq7XN5DBATloirfhclWsiqgDeXTKayjh46pYXUEGXsljSiLnpt96",
-	"CimVWKKKnb+poYQ1BbltXAoYb9EvlBtd8ve3qtv2RwGDfKG67a+Ok6Edgi+Se6O0O0mh5nR9d1mEG0wP",
-	"wZMLvZ5XRTGBVt4bXaVy8qdAE4K/6H5sakwT4wx9CKcTPIKRw+VD9X7z42wtzmUI2XbDb5ysRH6cqbtt",
-	"l71G3QPy4di0hg2UkB8u89C6KoL8DAAA//+7BezVcgYAAA==",
+	"H4sIAAAAAAAC/6xYX0/jSBL/KqW+e5iRmiRwo5Mu88ShkZbVsrAwq31ACHXclaQHu9vTXSZkkb/7qqvt",
+	"OIkNBAmeYru6/tevf8WzyFxROouWgpg+i1J5VSCh56fMFYWz9zfkURXxhbFiKn5W6NdCCqsKFFMR0lcp",
+	"QrbEQkUxWpfxy8y5HJUVdV23X1ntlXeFI9TfnjCryDjLlr0r0ZNBFsH2073R8VljyLwpk7A41+DmQEsE",
+	"FdY2g400+MpaYxf8jf2UEBBh/Hg8ZtHxRjSMn7eN1CMhxdz5QpGYCmPpv1+EbAMxlnCBXsQ4PP6sjEct",
+	"pre7Xt5txN3sB2Ykain+4FT1otvNstLaRCUqv9qRejpauKNG5Y/g7OharS4wBLXAqHs3JWwJOsUwW0Ms",
+	"kAQcLUbwbPT0z/MYVQ3GwllusodfXBXwKwTyxi6CBFsVM/RBQlM3fpXnEpT3ah1AWQ0ptACfClWGz6A8",
+	"gker0aMGFbb0Qm4Ivco7vfCAWDa1MR7wSWUE2iwMhZEYyF3AzGPqyt1Qr7ogaamInbCOIHeLBWqwzoOq",
+	"tCHUUa8hLFjJvz3OxVT8a9x1/LhpynHb6GxS1BtvOPLkDFHM0gfXq1UrwWOsQ0aogRy3r7MYQOW5W6GO",
+	"1YzvAvpH9MPp+plvjV4qKkdiCnQV9dP4PX2I7RAwc1Zz5Sur981BoZ5MURXRbGFs/Cmmx28OR3RoaCaa",
+	"XJ+5vCoGJj+hylAg63Low55VPt4Iv2L9m/fO940XTbl6yfprmTISTWEgWKkAHqPS1GWvO9WqfcWhCyT1",
+	"7REtDTlFjKrv6eQmu71Orl/24Mq7hccQ+g7M1pR+vImPUmCuyoB6UHrTPJOhk96tDjVCjlR+f/CBA4J+",
+	"IfXlVk4OyPomha+ZvMZQ5QO2tCLVb7xrtwoRWVvgfcB1ms+MK8wYH0eYEcNr9PFmjB0Dn369ufwdUnI+",
+	"S4ioGFood3N4VHmFIUndn11eXJ2efW+lt3FzAAw/siHfUfc3c7oposrzy7mY3h7kWToratnjIC1G9Id7",
+	"z4+7LU/c6qVmGi7wKcwUZctYkpgJ2RazQRrUTVFerckrqWnutJ43D7gehFnui7dxNh5vhfuwlq7vyhta",
+	"38RsJ5OdK1wCpoioPPqu2EuiMvFFY+eO3TCUxy+nC+sCmQyuvHtaw03kfadX59EJ9CHl8ng0GU1iEK5E",
+	"q0ojpuI/o8noREhRKlqyE2NfpVvHBXYl5kTFWpzryC1coOvK8oGOo73QSJ3IeJcp13K/yjyLiSkFrvPW",
+	"TEvYmcGhKR0acG6IAT7ezNE2H9c4V4w57IeQAm2E4dv2cdv+VjW70u+Hc5EYQUPt2t5tSTn/xjgE/Cot",
+	"COElf7n974P5G4d9Pp5MJlI0HCQ9TiZbl8kgE9l3+CYxHFBzQg+rpcmWoNKGAIFMnm82BxMgUzbDPDJa",
+	"qyHzqCixW2V7+4axgVBpCcqGFbPglaGo+mRy8pWTsbObhG4xAbVQxsLcu4JfBlKeZDrOzxHYG/I2gkub",
+	"ryMQQNNIoXQ2YJCsy2Dgc5HNtYySfSfTkeRIJMHQS1Uo0052zwnaKcR7E53zPZUgLLnZIJgEVZa5yXja",
+	"xpEhw6emyp8lED7RmJvmKDXMrvjTkdXxSCOYhUe+0bYkHq0eqVJlSxwp791qlNR8jaHDrKK+9bg4JKFU",
+	"3zSXvNIovUnUEpVG32XqNMuwpKF9dzMvdwkrMdD/nV6nTdpSezntubHBQ/XWVZq2SQb7mIMyV2bv9L4j",
+	"O6BNvkJ+0TRPPHAymXyYe3v3aS3FAeXZVb/hADNjFbdn/xYa6orXs9DkKwuPB8ltN+HuAWfxcF6xSy1r",
+	"edChbg048EDHN2r5jvI0R+5q7pGTycmHdUH/nztsYm/33KDgSu3hbQNEvAb3AVc22M1YamgQROPkpqHl",
+	"Fv/NpTjemJRaii8fPwxpz2zUn/xvYAtfIsM0LFUAcg4KZdfAQbfQ3txMe2FdI/n10SnD9VBkHUanv46R",
+	"cQO3XOz2rpbPEbDSrp/ITuVzMRXxn2bRkdgm/wwAmj/inigUAAA=",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
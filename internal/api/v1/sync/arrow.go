@@ -0,0 +1,133 @@
+package sync
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/agnosticeng/agp/internal/backend"
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/ipc"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+// arrowEncoder writes an Arrow IPC stream with a record batch per batch of rows; integers, floats
+// and booleans get native Arrow types, other values are strings as in CSV.
+type arrowEncoder struct {
+	w      io.Writer
+	schema *arrow.Schema
+	writer *ipc.Writer
+}
+
+// arrowType maps a column type name to an Arrow type, after its Nullable and LowCardinality wrappers.
+func arrowType(typ string) arrow.DataType {
+	for _, wrapper := range []string{"LowCardinality(", "Nullable("} {
+		if strings.HasPrefix(typ, wrapper) && strings.HasSuffix(typ, ")") {
+			typ = typ[len(wrapper) : len(typ)-1]
+		}
+	}
+
+	switch typ {
+	case "Int8", "Int16", "Int32", "Int64":
+		return arrow.PrimitiveTypes.Int64
+	case "UInt8", "UInt16", "UInt32", "UInt64":
+		return arrow.PrimitiveTypes.Uint64
+	case "Float32", "Float64":
+		return arrow.PrimitiveTypes.Float64
+	case "Bool":
+		return arrow.FixedWidthTypes.Boolean
+	default:
+		return arrow.BinaryTypes.String
+	}
+}
+
+func (enc *arrowEncoder) meta(schema backend.Schema) error {
+	var fields = make([]arrow.Field, len(schema))
+
+	for i, column := range schema {
+		fields[i] = arrow.Field{Name: column.Name, Type: arrowType(column.Type), Nullable: true}
+	}
+
+	enc.schema = arrow.NewSchema(fields, nil)
+	enc.writer = ipc.NewWriter(enc.w, ipc.WithSchema(enc.schema))
+	return nil
+}
+
+func (enc *arrowEncoder) rows(_ backend.Schema, rows []backend.Row) error {
+	var b = array.NewRecordBuilder(memory.DefaultAllocator, enc.schema)
+	defer b.Release()
+
+	for _, row := range rows {
+		for i, v := range row {
+			if err := appendArrowValue(b.Field(i), v); err != nil {
+				return fmt.Errorf("column %s: %w", enc.schema.Field(i).Name, err)
+			}
+		}
+	}
+
+	var rec = b.NewRecord()
+	defer rec.Release()
+
+	return enc.writer.Write(rec)
+}
+
+// end closes the stream on success only, a failed stream misses its end-of-stream marker.
+func (enc *arrowEncoder) end(_ *backend.Result, err error) error {
+	if err != nil {
+		return err
+	}
+
+	return enc.writer.Close()
+}
+
+func appendArrowValue(b array.Builder, v any) error {
+	var s, ok = valueText(v)
+
+	if !ok {
+		b.AppendNull()
+		return nil
+	}
+
+	switch b := b.(type) {
+	case *array.Int64Builder:
+		n, err := strconv.ParseInt(s, 10, 64)
+
+		if err != nil {
+			return err
+		}
+
+		b.Append(n)
+	case *array.Uint64Builder:
+		n, err := strconv.ParseUint(s, 10, 64)
+
+		if err != nil {
+			return err
+		}
+
+		b.Append(n)
+	case *array.Float64Builder:
+		f, err := strconv.ParseFloat(s, 64)
+
+		if err != nil {
+			return err
+		}
+
+		b.Append(f)
+	case *array.BooleanBuilder:
+		v, err := strconv.ParseBool(s)
+
+		if err != nil {
+			return err
+		}
+
+		b.Append(v)
+	case *array.StringBuilder:
+		b.Append(s)
+	default:
+		return fmt.Errorf("unsupported arrow type: %s", b.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+type TierConfig struct {
+	Tier string
+	// MaxConcurrency caps the sync queries of the tier running at once on each server process, the
+	// limit of a deployment being MaxConcurrency times its number of servers; unlimited when zero
+	MaxConcurrency int
+	// QueueTimeout is how long a query waits for a slot before being rejected with a 429
+	QueueTimeout time.Duration
+	// Timeout bounds the duration of sync queries of the tier, requested timeouts included
+	Timeout time.Duration
+	// AllowPromotion lets queries of the tier be promoted to async executions, which requires
+	// workers for the tier
+	AllowPromotion bool
+}
+
+// tierLimiter is a semaphore on the sync queries of a tier, it is held in memory so the limit applies
+// to each server process rather than to the whole deployment.
+type tierLimiter struct {
+	conf  TierConfig
+	slots chan struct{}
+}
+
+func newTierLimiter(conf TierConfig) *tierLimiter {
+	var l = tierLimiter{conf: conf}
+
+	if conf.MaxConcurrency > 0 {
+		l.slots = make(chan struct{}, conf.MaxConcurrency)
+	}
+
+	return &l
+}
+
+// acquire waits up to QueueTimeout for a slot, ok is false when none was free in time; release must
+// be called once the query completed.
+func (l *tierLimiter) acquire(ctx context.Context) (release func(), ok bool) {
+	if l.slots == nil {
+		return func() {}, true
+	}
+
+	release = func() { <-l.slots }
+
+	select {
+	case l.slots <- struct{}{}:
+		return release, true
+	default:
+	}
+
+	if l.conf.QueueTimeout <= 0 {
+		return nil, false
+	}
+
+	var timer = time.NewTimer(l.conf.QueueTimeout)
+	defer timer.Stop()
+
+	select {
+	case l.slots <- struct{}{}:
+		return release, true
+	case <-timer.C:
+		return nil, false
+	case <-ctx.Done():
+		return nil, false
+	}
+}
+
+// retryAfter is the delay in seconds advertised to rejected clients, at least the queue timeout.
+func (l *tierLimiter) retryAfter() int {
+	return max(1, int(math.Ceil(l.conf.QueueTimeout.Seconds())))
+}
+
+// apply bounds the timeout of a query by the one of the tier.
+func (l *tierLimiter) apply(q *runQuery) error {
+	if l.conf.Timeout <= 0 {
+		return nil
+	}
+
+	if q.timeout > l.conf.Timeout {
+		return fmt.Errorf("timeout exceeds the maximum of %s for tier %s", l.conf.Timeout, l.conf.Tier)
+	}
+
+	if q.timeout == 0 {
+		q.timeout = l.conf.Timeout
+	}
+
+	return nil
+}
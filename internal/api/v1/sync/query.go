@@ -0,0 +1,206 @@
+package sync
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type QueryConfig struct {
+	// AllowedSettings are the query settings callers can set, none by default
+	AllowedSettings []string
+	// MaxTimeout bounds the timeout callers can set, unbounded when zero
+	MaxTimeout time.Duration
+}
+
+// runQuery is a query to run, from a text/plain or an application/json request body.
+type runQuery struct {
+	sql        string
+	parameters map[string]string
+	settings   map[string]string
+	timeout    time.Duration
+}
+
+// requestQuery validates the request body, errors are caused by the caller.
+func (srv *Server) requestQuery(request PostRunRequestObject) (*runQuery, error) {
+	var q = runQuery{parameters: make(map[string]string)}
+
+	if request.TextBody != nil {
+		q.sql = *request.TextBody
+		return &q, nil
+	}
+
+	if request.JSONBody == nil {
+		return nil, fmt.Errorf("missing query")
+	}
+
+	var body = request.JSONBody
+
+	q.sql = body.Sql
+
+	if body.Parameters != nil {
+		for name, raw := range *body.Parameters {
+			v, err := decodeValue(raw)
+
+			if err != nil {
+				return nil, fmt.Errorf("invalid parameter %s: %w", name, err)
+			}
+
+			s, err := parameterValue(v, false)
+
+			if err != nil {
+				return nil, fmt.Errorf("invalid parameter %s: %w", name, err)
+			}
+
+			q.parameters[name] = s
+		}
+	}
+
+	if body.Secrets != nil {
+		for _, secret := range *body.Secrets {
+			if _, found := q.parameters[secret.Key]; found {
+				return nil, fmt.Errorf("secret %s is also a parameter", secret.Key)
+			}
+
+			q.parameters[secret.Key] = secret.Value
+		}
+	}
+
+	if body.Settings != nil {
+		q.settings = make(map[string]string)
+
+		for name, raw := range *body.Settings {
+			if !slices.Contains(srv.queryConf.AllowedSettings, name) {
+				return nil, fmt.Errorf("setting %s is not allowed", name)
+			}
+
+			v, err := decodeValue(raw)
+
+			if err != nil {
+				return nil, fmt.Errorf("invalid setting %s: %w", name, err)
+			}
+
+			s, err := settingValue(v)
+
+			if err != nil {
+				return nil, fmt.Errorf("invalid setting %s: %w", name, err)
+			}
+
+			q.settings[name] = s
+		}
+	}
+
+	if body.Timeout != nil {
+		q.timeout = time.Duration(*body.Timeout) * time.Second
+
+		if q.timeout <= 0 {
+			return nil, fmt.Errorf("timeout must be positive")
+		}
+
+		if srv.queryConf.MaxTimeout > 0 && q.timeout > srv.queryConf.MaxTimeout {
+			return nil, fmt.Errorf("timeout exceeds the maximum of %s", srv.queryConf.MaxTimeout)
+		}
+	}
+
+	return &q, nil
+}
+
+// decodeValue decodes a JSON value, numbers being kept as json.Number so that integers beyond 2^53
+// and decimals are passed as they were written.
+func decodeValue(raw json.RawMessage) (any, error) {
+	var (
+		dec = json.NewDecoder(bytes.NewReader(raw))
+		v   any
+	)
+
+	dec.UseNumber()
+
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// parameterValue renders a JSON value in the text format of ClickHouse query parameters; nested
+// values are literals, e.g. strings are quoted in arrays.
+func parameterValue(v any, nested bool) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		if nested {
+			return "NULL", nil
+		}
+
+		return `\N`, nil
+	case string:
+		if nested {
+			return quoteString(v), nil
+		}
+
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case []any:
+		var elems = make([]string, len(v))
+
+		for i, e := range v {
+			s, err := parameterValue(e, true)
+
+			if err != nil {
+				return "", err
+			}
+
+			elems[i] = s
+		}
+
+		return "[" + strings.Join(elems, ",") + "]", nil
+	case map[string]any:
+		var (
+			keys  = slices.Sorted(maps.Keys(v))
+			elems = make([]string, len(keys))
+		)
+
+		for i, k := range keys {
+			s, err := parameterValue(v[k], true)
+
+			if err != nil {
+				return "", err
+			}
+
+			elems[i] = quoteString(k) + ":" + s
+		}
+
+		return "{" + strings.Join(elems, ",") + "}", nil
+	default:
+		return "", fmt.Errorf("unsupported value type %T", v)
+	}
+}
+
+func quoteString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+// settingValue renders a scalar JSON value, booleans being 0 or 1.
+func settingValue(v any) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		if v {
+			return "1", nil
+		}
+
+		return "0", nil
+	default:
+		return "", fmt.Errorf("unsupported value type %T", v)
+	}
+}
@@ -0,0 +1,113 @@
+package sync
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParameterValue(t *testing.T) {
+	var cases = []struct {
+		value string
+		want  string
+		err   bool
+	}{
+		{value: `null`, want: `\N`},
+		{value: `"it's"`, want: `it's`},
+		{value: `""`, want: ``},
+		{value: `42`, want: `42`},
+		{value: `-1.5`, want: `-1.5`},
+		// numbers keep their digits, beyond the precision of a float64 too
+		{value: `18446744073709551615`, want: `18446744073709551615`},
+		{value: `9007199254740993`, want: `9007199254740993`},
+		{value: `0.10000000000000000001`, want: `0.10000000000000000001`},
+		{value: `1e3`, want: `1e3`},
+		{value: `true`, want: `true`},
+		{value: `false`, want: `false`},
+		{value: `[]`, want: `[]`},
+		{value: `[1, "a", null, true]`, want: `[1,'a',NULL,true]`},
+		{value: `["it's", "back\\slash"]`, want: `['it\'s','back\\slash']`},
+		{value: `[[1, 2], [3]]`, want: `[[1,2],[3]]`},
+		{value: `{"b": 2, "a": "x"}`, want: `{'a':'x','b':2}`},
+		{value: `{"k": [18446744073709551615]}`, want: `{'k':[18446744073709551615]}`},
+		{value: `{}`, want: `{}`},
+		{value: `[1,`, err: true},
+		{value: ``, err: true},
+	}
+
+	for _, c := range cases {
+		v, err := decodeValue(json.RawMessage(c.value))
+
+		var s string
+
+		if err == nil {
+			s, err = parameterValue(v, false)
+		}
+
+		if c.err {
+			if err == nil {
+				t.Errorf("%s: expected an error, got %q", c.value, s)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: %v", c.value, err)
+		} else if s != c.want {
+			t.Errorf("%s: got %q, want %q", c.value, s, c.want)
+		}
+	}
+}
+
+func TestParameterValueUnsupportedType(t *testing.T) {
+	if s, err := parameterValue(struct{}{}, false); err == nil {
+		t.Errorf("expected an error, got %q", s)
+	}
+
+	if s, err := parameterValue([]any{1.5}, false); err == nil {
+		t.Errorf("expected an error for a nested float64, got %q", s)
+	}
+}
+
+func TestSettingValue(t *testing.T) {
+	var cases = []struct {
+		value string
+		want  string
+		err   bool
+	}{
+		{value: `"best_effort"`, want: `best_effort`},
+		{value: `1000000`, want: `1000000`},
+		{value: `18446744073709551615`, want: `18446744073709551615`},
+		{value: `0.5`, want: `0.5`},
+		{value: `true`, want: `1`},
+		{value: `false`, want: `0`},
+		{value: `null`, err: true},
+		{value: `[1]`, err: true},
+		{value: `{"a": 1}`, err: true},
+		{value: `tru`, err: true},
+	}
+
+	for _, c := range cases {
+		v, err := decodeValue(json.RawMessage(c.value))
+
+		var s string
+
+		if err == nil {
+			s, err = settingValue(v)
+		}
+
+		if c.err {
+			if err == nil {
+				t.Errorf("%s: expected an error, got %q", c.value, s)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: %v", c.value, err)
+		} else if s != c.want {
+			t.Errorf("%s: got %q, want %q", c.value, s, c.want)
+		}
+	}
+}
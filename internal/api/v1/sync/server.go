@@ -1,43 +1,76 @@
 package sync
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"io"
 	"log/slog"
+	"sync/atomic"
+	"time"
 
 	v1 "github.com/agnosticeng/agp/internal/api/v1"
+	"github.com/agnosticeng/agp/internal/async_executor"
+	"github.com/agnosticeng/agp/internal/audit"
 	"github.com/agnosticeng/agp/internal/backend"
 	"github.com/agnosticeng/agp/internal/utils"
-	"github.com/agnosticeng/agp/pkg/json_text_event_stream"
+	"github.com/google/uuid"
 	"github.com/samber/lo"
 	slogctx "github.com/veqryn/slog-context"
 )
 
+const defaultBatchSize = 1000
+
 type BackendTier struct {
 	Tier    string
 	Backend backend.Backend
 }
 
 type Server struct {
-	logger *slog.Logger
-	bkds   []BackendTier
+	logger    *slog.Logger
+	bkds      []BackendTier
+	aex       *async_executor.AsyncExecutor
+	queryConf QueryConfig
+	limiters  map[string]*tierLimiter
 }
 
+// NewServer creates a sync API server; usage is only recorded, and queries can only be promoted to
+// async executions, when aex is not nil.
 func NewServer(
 	ctx context.Context,
 	bkds []BackendTier,
+	aex *async_executor.AsyncExecutor,
+	queryConf QueryConfig,
+	tiers []TierConfig,
 ) (*Server, error) {
 	if len(bkds) == 0 {
 		return nil, fmt.Errorf("at least one backend tier must be specified")
 	}
 
+	var limiters = make(map[string]*tierLimiter)
+
+	for _, t := range tiers {
+		limiters[t.Tier] = newTierLimiter(t)
+	}
+
 	return &Server{
-		logger: slogctx.FromCtx(ctx),
-		bkds:   bkds,
+		logger:    slogctx.FromCtx(ctx),
+		bkds:      bkds,
+		aex:       aex,
+		queryConf: queryConf,
+		limiters:  limiters,
 	}, nil
 }
 
+// limiter returns the limiter of a tier, tiers without configuration are unlimited.
+func (srv *Server) limiter(tier string) *tierLimiter {
+	if l, found := srv.limiters[tier]; found {
+		return l
+	}
+
+	return newTierLimiter(TierConfig{Tier: tier})
+}
+
 func (srv *Server) PostRun(ctx context.Context, request PostRunRequestObject) (PostRunResponseObject, error) {
 	var claims = v1.ClaimsFromContext(ctx)
 
@@ -47,39 +80,217 @@ func (srv *Server) PostRun(ctx context.Context, request PostRunRequestObject) (P
 		return nil, fmt.Errorf("no backend found for tier: %s", claims.Tier)
 	}
 
-	if !utils.DerefOr(request.Params.Stream, false) {
-		res, err := bkd.Backend.ExecuteQuery(
-			ctx,
-			*request.Body,
+	var limiter = srv.limiter(claims.Tier)
+
+	q, err := srv.requestQuery(request)
+
+	if err == nil {
+		err = limiter.apply(q)
+	}
+
+	var (
+		promoteAfter = time.Duration(utils.Deref(request.Params.PromoteAfter)) * time.Second
+		contentType  = "text/event-stream"
+		stream       = utils.DerefOr(request.Params.Stream, false)
+	)
+
+	if !stream {
+		contentType, stream = negotiateStream(utils.Deref(request.Params.Accept))
+	}
+
+	if err == nil && promoteAfter > 0 {
+		err = srv.checkPromotion(limiter, q, stream)
+	}
+
+	if err != nil {
+		srv.logger.Debug("invalid query", "error", err.Error())
+		return PostRun400JSONResponse{Message: err.Error()}, nil
+	}
+
+	release, ok := limiter.acquire(ctx)
+
+	if !ok {
+		return PostRun429Response{Headers: PostRun429ResponseHeaders{RetryAfter: limiter.retryAfter()}}, nil
+	}
+
+	var (
+		t0           = time.Now()
+		lastProgress atomic.Pointer[backend.Progress]
+		queryId      = "agp-sync-" + uuid.Must(uuid.NewV7()).String()
+		format       = backend.Format(utils.DerefOr(request.Params.Format, JSON))
+		runCtx       = ctx
+		cancel       = context.CancelFunc(func() {})
+		runOpts      = []backend.RunOption{
+			backend.WithQueryId(queryId),
 			backend.WithQuotaKey(claims.QuotaKey),
+			backend.WithParameters(q.parameters),
+			backend.WithSettings(q.settings),
+		}
+	)
+
+	if q.timeout > 0 {
+		runCtx, cancel = context.WithTimeout(ctx, q.timeout)
+	}
+
+	if !stream {
+		var (
+			done        = make(chan struct{})
+			promotion   <-chan time.Time
+			res         *backend.Result
+			runCancel   context.CancelFunc
+			queryCancel = cancel
 		)
 
+		// the query context is canceled on promotion, the slot being released once the query is aborted
+		runCtx, runCancel = context.WithCancel(runCtx)
+		cancel = func() { runCancel(); queryCancel() }
+
+		go func() {
+			defer close(done)
+			defer release()
+			defer cancel()
+
+			res, err = bkd.Backend.ExecuteQuery(
+				runCtx,
+				q.sql,
+				append(runOpts, backend.WithProgressHandler(func(p backend.Progress) { lastProgress.Store(&p) }))...,
+			)
+		}()
+
+		if promoteAfter > 0 {
+			var timer = time.NewTimer(promoteAfter)
+			defer timer.Stop()
+			promotion = timer.C
+		}
+
+		select {
+		case <-done:
+		case <-promotion:
+			select {
+			case <-done:
+			default:
+				// the usage of a promoted query is recorded by the async execution running it again
+				cancel()
+				return srv.promote(ctx, claims, q)
+			}
+		}
+
+		srv.recordUsage(claims, t0, lastProgress.Load(), res, 0)
+		audit.ReportQuery(ctx, err)
+
 		if err != nil {
 			return nil, err
 		}
 
-		return PostRun200JSONResponse(*(v1.ToResult(res))), nil
+		return PostRun200JSONResponse(*(v1.ToResult(res, format))), nil
 	}
 
 	var (
 		r, w = io.Pipe()
-		enc  = json_text_event_stream.NewJSONTextEventStreamEncoder(w)
+		cw   = &utils.CountingWriter{Writer: w}
+		rs   = newRowsStream(streamFormats[contentType](cw, format), utils.DerefOr(request.Params.BatchSize, defaultBatchSize))
 	)
 
 	go func() {
-		defer w.Close()
+		defer release()
+		defer cancel()
 
 		res, err := bkd.Backend.ExecuteQuery(
-			ctx,
-			*request.Body,
-			backend.WithQuotaKey(claims.QuotaKey),
-			backend.WithProgressHandler(func(p backend.Progress) {
-				enc.Encode("progress", v1.ProgressEvent{Progress: v1.ToProgress(&p)})
-			}),
+			runCtx,
+			q.sql,
+			append(
+				runOpts,
+				backend.WithProgressHandler(func(p backend.Progress) {
+					lastProgress.Store(&p)
+					rs.progress(p)
+				}),
+				backend.WithRowHandler(rs.add),
+			)...,
 		)
 
-		enc.Encode("result", v1.ToResultEvent(res, err))
+		// rows were already sent, a failure can only abort the response
+		var endErr = rs.end(res, err)
+
+		audit.ReportQuery(ctx, cmp.Or(err, endErr))
+		w.CloseWithError(endErr)
+		srv.recordUsage(claims, t0, lastProgress.Load(), res, cw.N)
 	}()
 
-	return PostRun200TexteventStreamResponse{Body: r}, nil
+	return streamResponse{contentType: contentType, body: r}, nil
+}
+
+// checkPromotion tells whether a query can be promoted to an async execution, which has no
+// settings and returns the result as a JSON document.
+func (srv *Server) checkPromotion(limiter *tierLimiter, q *runQuery, stream bool) error {
+	switch {
+	case srv.aex == nil:
+		return fmt.Errorf("async executions are not available")
+	case !limiter.conf.AllowPromotion:
+		return fmt.Errorf("promotion is not allowed for tier %s", limiter.conf.Tier)
+	case stream:
+		return fmt.Errorf("streamed queries cannot be promoted")
+	case len(q.settings) > 0:
+		return fmt.Errorf("queries with settings cannot be promoted")
+	default:
+		return nil
+	}
+}
+
+// promote runs the query again from the start as an async execution, parameters being kept as its
+// secrets and its timeout, the tier one included, still applying.
+func (srv *Server) promote(ctx context.Context, claims *v1.Claims, q *runQuery) (PostRunResponseObject, error) {
+	var opts = async_executor.CreateOptions{
+		Tier:    claims.Tier,
+		Secrets: q.parameters,
+	}
+
+	if q.timeout > 0 {
+		opts.Timeout = &q.timeout
+	}
+
+	ex, err := srv.aex.Create(ctx, claims.QuotaKey, q.sql, opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	audit.ReportPromotion(ctx, ex.Id)
+
+	return PostRun202JSONResponse{
+		Body:    PromotedExecution{ExecutionId: ex.Id},
+		Headers: PostRun202ResponseHeaders{Location: fmt.Sprintf("/v1/async/executions/%d", ex.Id)},
+	}, nil
+}
+
+func (srv *Server) recordUsage(
+	claims *v1.Claims,
+	t0 time.Time,
+	progress *backend.Progress,
+	res *backend.Result,
+	resultBytes int64,
+) {
+	if srv.aex == nil {
+		return
+	}
+
+	var usage = async_executor.Usage{
+		QuotaKey:    claims.QuotaKey,
+		Tier:        claims.Tier,
+		Source:      async_executor.UsageSourceSync,
+		Elapsed:     time.Since(t0),
+		ResultBytes: resultBytes,
+	}
+
+	if progress != nil {
+		usage.RowsRead = int64(progress.Rows)
+		usage.BytesRead = int64(progress.Bytes)
+	}
+
+	if res != nil {
+		usage.ResultRows = res.Rows
+	}
+
+	if err := srv.aex.RecordUsage(context.Background(), usage); err != nil {
+		srv.logger.Error(err.Error())
+	}
 }
@@ -0,0 +1,351 @@
+package sync
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	stdsync "sync"
+	"time"
+
+	v1 "github.com/agnosticeng/agp/internal/api/v1"
+	"github.com/agnosticeng/agp/internal/backend"
+	"github.com/agnosticeng/agp/pkg/json_text_event_stream"
+)
+
+// rowsFlushInterval bounds how long scanned rows wait for their batch to fill up.
+const rowsFlushInterval = time.Second
+
+// rowsEncoder writes a streamed result; rows are only written after meta, and end is called once
+// the query completed, with a nil result on failure.
+type rowsEncoder interface {
+	meta(schema backend.Schema) error
+	rows(schema backend.Schema, rows []backend.Row) error
+	end(res *backend.Result, err error) error
+}
+
+// progressEncoder is implemented by encoders that report progress.
+type progressEncoder interface {
+	progress(p backend.Progress) error
+}
+
+// streamFormats are the streamed response formats, by media type.
+var streamFormats = map[string]func(w io.Writer, format backend.Format) rowsEncoder{
+	"text/event-stream": func(w io.Writer, format backend.Format) rowsEncoder {
+		return &sseEncoder{enc: json_text_event_stream.NewJSONTextEventStreamEncoder(w), format: format}
+	},
+	"application/x-ndjson": func(w io.Writer, format backend.Format) rowsEncoder {
+		return &ndjsonEncoder{w: w, format: format}
+	},
+	"text/csv": func(w io.Writer, _ backend.Format) rowsEncoder {
+		return &csvEncoder{w: csv.NewWriter(w)}
+	},
+	"application/vnd.apache.arrow.stream": func(w io.Writer, _ backend.Format) rowsEncoder {
+		return &arrowEncoder{w: w}
+	},
+}
+
+// negotiateStream returns the first streamed media type an Accept header asks for, or false when
+// the result must be sent as a single JSON document.
+func negotiateStream(accept string) (string, bool) {
+	for _, item := range strings.Split(accept, ",") {
+		var mediaType, params, _ = strings.Cut(strings.TrimSpace(item), ";")
+
+		mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+
+		if rejected(params) {
+			continue
+		}
+
+		if mediaType == "application/json" {
+			return "", false
+		}
+
+		if _, found := streamFormats[mediaType]; found {
+			return mediaType, true
+		}
+	}
+
+	return "", false
+}
+
+// rejected reports whether the parameters of an Accept item have a zero quality.
+func rejected(params string) bool {
+	for _, param := range strings.Split(params, ";") {
+		var k, v, found = strings.Cut(strings.TrimSpace(param), "=")
+
+		if found && strings.TrimSpace(k) == "q" {
+			q, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			return err != nil || q <= 0
+		}
+	}
+
+	return false
+}
+
+// rowsStream batches the rows handed by a backend, progress reports may come from another goroutine.
+type rowsStream struct {
+	mu        stdsync.Mutex
+	enc       rowsEncoder
+	batchSize int
+	batch     []backend.Row
+	started   bool
+	lastFlush time.Time
+}
+
+func newRowsStream(enc rowsEncoder, batchSize int) *rowsStream {
+	return &rowsStream{enc: enc, batchSize: batchSize}
+}
+
+func (s *rowsStream) add(meta backend.Schema, row backend.Row) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.start(meta); err != nil {
+		return err
+	}
+
+	s.batch = append(s.batch, row)
+
+	if len(s.batch) >= s.batchSize || time.Since(s.lastFlush) >= rowsFlushInterval {
+		return s.flush(meta)
+	}
+
+	return nil
+}
+
+func (s *rowsStream) progress(p backend.Progress) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if enc, ok := s.enc.(progressEncoder); ok {
+		enc.progress(p)
+	}
+}
+
+// end flushes the pending rows and completes the stream, it returns the error that must abort the
+// response, if any.
+func (s *rowsStream) end(res *backend.Result, err error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err == nil {
+		if err := s.start(res.Meta); err != nil {
+			return err
+		}
+
+		if err := s.flush(res.Meta); err != nil {
+			return err
+		}
+	}
+
+	return s.enc.end(res, err)
+}
+
+func (s *rowsStream) start(meta backend.Schema) error {
+	if s.started {
+		return nil
+	}
+
+	s.started = true
+	s.lastFlush = time.Now()
+	return s.enc.meta(meta)
+}
+
+func (s *rowsStream) flush(meta backend.Schema) error {
+	if len(s.batch) == 0 {
+		return nil
+	}
+
+	var err = s.enc.rows(meta, s.batch)
+
+	s.batch = s.batch[:0]
+	s.lastFlush = time.Now()
+	return err
+}
+
+type sseEncoder struct {
+	enc    *json_text_event_stream.JSONTextEventStreamEncoder
+	format backend.Format
+}
+
+func (enc *sseEncoder) progress(p backend.Progress) error {
+	return enc.enc.Encode("progress", v1.ProgressEvent{Progress: v1.ToProgress(&p)})
+}
+
+func (enc *sseEncoder) meta(schema backend.Schema) error {
+	var columns = make([]v1.Column, len(schema))
+
+	for i, column := range schema {
+		columns[i] = v1.Column(column)
+	}
+
+	return enc.enc.Encode("meta", v1.MetaEvent{Meta: &columns})
+}
+
+func (enc *sseEncoder) rows(schema backend.Schema, rows []backend.Row) error {
+	var data = make([]any, len(rows))
+
+	for i, row := range rows {
+		data[i] = backend.FormatRow(schema, row, enc.format)
+	}
+
+	return enc.enc.Encode("rows", v1.RowsEvent{Data: &data})
+}
+
+// end sends the final result event, without data since rows were sent in rows events; errors are
+// reported in the event.
+func (enc *sseEncoder) end(res *backend.Result, err error) error {
+	var ev = v1.ToResultEvent(res, err, enc.format)
+
+	if ev != nil {
+		ev.Data = nil
+	}
+
+	return enc.enc.Encode("result", ev)
+}
+
+// ndjsonEncoder writes a JSON value per row, objects or arrays depending on the format.
+type ndjsonEncoder struct {
+	w      io.Writer
+	format backend.Format
+}
+
+func (enc *ndjsonEncoder) meta(backend.Schema) error {
+	return nil
+}
+
+func (enc *ndjsonEncoder) rows(schema backend.Schema, rows []backend.Row) error {
+	var je = json.NewEncoder(enc.w)
+
+	for _, row := range rows {
+		if err := je.Encode(backend.FormatRow(schema, row, enc.format)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (enc *ndjsonEncoder) end(_ *backend.Result, err error) error {
+	return err
+}
+
+// csvEncoder writes a header with the column names, then a record per row; nulls are empty fields
+// and composite values are JSON.
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+func (enc *csvEncoder) meta(schema backend.Schema) error {
+	var header = make([]string, len(schema))
+
+	for i, column := range schema {
+		header[i] = column.Name
+	}
+
+	return enc.w.Write(header)
+}
+
+func (enc *csvEncoder) rows(_ backend.Schema, rows []backend.Row) error {
+	for _, row := range rows {
+		var record = make([]string, len(row))
+
+		for i, v := range row {
+			record[i], _ = valueText(v)
+		}
+
+		if err := enc.w.Write(record); err != nil {
+			return err
+		}
+	}
+
+	enc.w.Flush()
+	return enc.w.Error()
+}
+
+func (enc *csvEncoder) end(_ *backend.Result, err error) error {
+	enc.w.Flush()
+
+	if err != nil {
+		return err
+	}
+
+	return enc.w.Error()
+}
+
+// valueText returns the text of a value for text formats, ok is false for nulls.
+func valueText(v any) (string, bool) {
+	switch v := v.(type) {
+	case nil:
+		return "", false
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case []byte:
+		return string(v), true
+	case time.Time:
+		return v.Format(time.RFC3339Nano), true
+	}
+
+	js, err := json.Marshal(v)
+
+	if err != nil {
+		return "", false
+	}
+
+	if js[0] == '"' {
+		var s string
+		json.Unmarshal(js, &s)
+		return s, true
+	}
+
+	if string(js) == "null" {
+		return "", false
+	}
+
+	return string(js), true
+}
+
+// streamResponse copies a stream to the client, flushing as data arrives so that rows can be
+// rendered before the query completes; the status is sent before the query runs, so failures abort
+// the connection for the client to see an incomplete body.
+type streamResponse struct {
+	contentType string
+	body        io.ReadCloser
+}
+
+func (response streamResponse) VisitPostRunResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", response.contentType)
+	w.WriteHeader(http.StatusOK)
+
+	defer response.body.Close()
+
+	var (
+		rc  = http.NewResponseController(w)
+		buf = make([]byte, 32*1024)
+	)
+
+	for {
+		n, err := response.body.Read(buf)
+
+		if n > 0 {
+			if _, err := w.Write(buf[:n]); err != nil {
+				panic(http.ErrAbortHandler)
+			}
+
+			rc.Flush()
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			panic(http.ErrAbortHandler)
+		}
+	}
+}
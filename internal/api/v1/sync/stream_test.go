@@ -0,0 +1,146 @@
+package sync
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/agnosticeng/agp/internal/backend"
+	"github.com/agnosticeng/agp/pkg/client_ip_middleware"
+)
+
+// rowsBackend hands rows to the row handler, then fails with err when it is set.
+type rowsBackend struct {
+	rows int
+	err  error
+}
+
+func (bkd rowsBackend) ExecuteQuery(ctx context.Context, query string, opts ...backend.RunOption) (*backend.Result, error) {
+	var (
+		runOpts = backend.BuildRunOptions(opts...)
+		res     = backend.Result{Meta: backend.Schema{{Name: "n", Type: "Int64"}}}
+	)
+
+	for i := 0; i < bkd.rows; i++ {
+		if err := runOpts.RowHandler(res.Meta, backend.Row{int64(i)}); err != nil {
+			return nil, err
+		}
+
+		res.Rows++
+	}
+
+	if bkd.err != nil {
+		return nil, bkd.err
+	}
+
+	return &res, nil
+}
+
+func (bkd rowsBackend) Ping(context.Context) error { return nil }
+func (bkd rowsBackend) Close() error               { return nil }
+
+func runStream(t *testing.T, bkd backend.Backend) (*http.Response, []byte, error) {
+	t.Helper()
+
+	srv, err := NewServer(context.Background(), []BackendTier{{Backend: bkd}}, nil, QueryConfig{}, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ts = httptest.NewServer(client_ip_middleware.ClientIP(Handler(NewStrictHandler(srv, nil))))
+	defer ts.Close()
+
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/run?batch_size=1", strings.NewReader("SELECT n"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Content-Type", "text/plain")
+	req.Header.Set("Accept", "application/x-ndjson")
+
+	resp, err := http.DefaultClient.Do(req)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	return resp, body, err
+}
+
+func TestStreamCompletes(t *testing.T) {
+	resp, body, err := runStream(t, rowsBackend{rows: 3})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/x-ndjson" {
+		t.Fatalf("got status %d and content type %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	if want := "{\"n\":0}\n{\"n\":1}\n{\"n\":2}\n"; string(body) != want {
+		t.Errorf("got body %q, want %q", body, want)
+	}
+}
+
+func TestStreamAbortsOnQueryError(t *testing.T) {
+	resp, body, err := runStream(t, rowsBackend{rows: 1000, err: fmt.Errorf("query failed")})
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("got status %d", resp.StatusCode)
+	}
+
+	// the status was sent with the first rows, the transfer must not look complete
+	if err == nil {
+		t.Fatalf("expected the transfer to be aborted, got a %d bytes body", len(body))
+	}
+
+	if bytes.Contains(body, []byte("query failed")) {
+		t.Error("the error must not be appended to the body")
+	}
+}
+
+func TestNegotiateStream(t *testing.T) {
+	var cases = []struct {
+		accept    string
+		mediaType string
+		stream    bool
+	}{
+		{accept: "", stream: false},
+		{accept: "*/*", stream: false},
+		{accept: "application/json", stream: false},
+		{accept: "text/event-stream", mediaType: "text/event-stream", stream: true},
+		{accept: "application/x-ndjson", mediaType: "application/x-ndjson", stream: true},
+		{accept: "text/csv", mediaType: "text/csv", stream: true},
+		{accept: "application/vnd.apache.arrow.stream", mediaType: "application/vnd.apache.arrow.stream", stream: true},
+		{accept: "Text/CSV; charset=utf-8", mediaType: "text/csv", stream: true},
+		{accept: "text/html, text/csv", mediaType: "text/csv", stream: true},
+		// the first acceptable format wins, JSON included
+		{accept: "application/json, text/csv", stream: false},
+		{accept: "application/x-ndjson, text/csv", mediaType: "application/x-ndjson", stream: true},
+		// zero or invalid qualities reject the item
+		{accept: "text/csv;q=0, application/x-ndjson", mediaType: "application/x-ndjson", stream: true},
+		{accept: "text/csv; q=0.0", stream: false},
+		{accept: "text/csv;q=abc", stream: false},
+		{accept: "application/json;q=0, text/csv;q=0.5", mediaType: "text/csv", stream: true},
+		{accept: "text/csv;q=1", mediaType: "text/csv", stream: true},
+	}
+
+	for _, c := range cases {
+		mediaType, stream := negotiateStream(c.accept)
+
+		if mediaType != c.mediaType || stream != c.stream {
+			t.Errorf("%q: got (%q, %t), want (%q, %t)", c.accept, mediaType, stream, c.mediaType, c.stream)
+		}
+	}
+}
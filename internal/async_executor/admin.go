@@ -0,0 +1,105 @@
+package async_executor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/agnosticeng/agp/internal/async_executor/queries"
+	"github.com/jackc/pgx/v5"
+	"github.com/samber/lo"
+)
+
+// CancelExecutions cancels the PENDING and RUNNING executions matching the filter,
+// workers notice it on their next heartbeat and abort the query.
+func (aex *AsyncExecutor) CancelExecutions(ctx context.Context, actor string, filter ExecutionFilter) (int64, error) {
+	if filter.IsEmpty() {
+		return 0, fmt.Errorf("filter must not be empty")
+	}
+
+	var args = filter.args()
+	args["actor"] = actor
+
+	rows, err := queries.Query(ctx, aex.pool, "cancel_executions.sql", args)
+
+	if err != nil {
+		return 0, err
+	}
+
+	exs, err := pgx.CollectRows(rows, pgx.RowToStructByName[Execution])
+
+	if err != nil {
+		return 0, err
+	}
+
+	for _, ex := range exs {
+		aex.auditExecution(&ex)
+	}
+
+	return int64(len(exs)), nil
+}
+
+// RequeueExecutions puts the RUNNING executions matching the filter back to PENDING. The worker
+// running them is not interrupted: it aborts the query on its next heartbeat, or, when the query
+// already completed, finishes uploading and then deletes the result it can no longer record. The
+// next attempt writes to its own result path with its own backend query id meanwhile.
+func (aex *AsyncExecutor) RequeueExecutions(ctx context.Context, actor string, filter ExecutionFilter) (int64, error) {
+	if filter.IsEmpty() {
+		return 0, fmt.Errorf("filter must not be empty")
+	}
+
+	var args = filter.args()
+	args["actor"] = actor
+
+	rows, err := queries.Query(ctx, aex.pool, "requeue_executions.sql", args)
+
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := countRows(rows)
+	return int64(count), err
+}
+
+func (aex *AsyncExecutor) ListLeases(ctx context.Context) ([]*Lease, error) {
+	rows, err := queries.Query(ctx, aex.pool, "list_leases.sql", nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	leases, err := pgx.CollectRows(rows, pgx.RowToStructByName[Lease])
+
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.ToSlicePtr(leases), nil
+}
+
+// ReleaseLease deletes a lease whoever owns it, the next candidate takes it over on its next attempt.
+func (aex *AsyncExecutor) ReleaseLease(ctx context.Context, key string) (bool, error) {
+	rows, err := queries.Query(ctx, aex.pool, "release_lease.sql", pgx.NamedArgs{"key": key})
+
+	if err != nil {
+		return false, err
+	}
+
+	count, err := countRows(rows)
+	return count > 0, err
+}
+
+func (aex *AsyncExecutor) TierStats(ctx context.Context) ([]*TierStats, error) {
+	rows, err := queries.Query(ctx, aex.pool, "tier_stats.sql", nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := pgx.CollectRows(rows, pgx.RowToStructByName[TierStats])
+
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.ToSlicePtr(stats), nil
+}
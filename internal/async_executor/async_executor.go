@@ -2,16 +2,21 @@ package async_executor
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/url"
+	"time"
 
 	"github.com/agnosticeng/agp/internal/async_executor/queries"
+	"github.com/agnosticeng/agp/internal/audit"
+	"github.com/agnosticeng/agp/internal/backend"
 	"github.com/agnosticeng/agp/internal/query_hasher"
 	"github.com/agnosticeng/objstr"
+	"github.com/agnosticeng/objstr/types"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jackc/pgx/v5/tracelog"
@@ -24,6 +29,21 @@ type AsyncExecutorConfig struct {
 	Dsn                      string
 	ResultStoragePrefix      string
 	ResultStorageCompression ResultCompression
+	// ResultStorageCompressionLevel is codec specific, the codec default is used when zero
+	ResultStorageCompressionLevel int
+	// ResultStorageFormat is JSON (rows as objects, default) or JSON_COMPACT (rows as arrays)
+	ResultStorageFormat ResultFormat
+	// ResultStoragePathTemplate is the path of results under their prefix, with {{id}} (suffixed with the
+	// attempt for requeued executions), {{tier}}, {{created_by}}, {{query_id}}, {{yyyy}}, {{mm}}, {{dd}},
+	// {{hh}} (creation time, UTC) and {{ext}} placeholders
+	ResultStoragePathTemplate string
+	// ResultStorageTiers overrides ResultStoragePrefix for some tiers
+	ResultStorageTiers []ResultStorageTierConfig
+	// ResultPreviewRows is the number of rows kept inline in the database for previews, disabled when zero
+	ResultPreviewRows     int
+	ResultPreviewMaxBytes int
+	// DisableResultColumnStats skips computing per-column statistics of results
+	DisableResultColumnStats bool
 }
 
 type AsyncExecutor struct {
@@ -32,11 +52,13 @@ type AsyncExecutor struct {
 	pool        *pgxpool.Pool
 	os          *objstr.ObjectStore
 	queryHasher query_hasher.QueryHasher
+	auditSink   audit.Sink
 }
 
 func NewAsyncExecutor(
 	ctx context.Context,
 	queryHasher query_hasher.QueryHasher,
+	auditSink audit.Sink,
 	conf AsyncExecutorConfig,
 ) (*AsyncExecutor, error) {
 	var logger = slogctx.FromCtx(ctx)
@@ -45,12 +67,47 @@ func NewAsyncExecutor(
 		return nil, fmt.Errorf("a query hasher must be provider")
 	}
 
+	if auditSink == nil {
+		auditSink = audit.NopSink{}
+	}
+
 	_, err := url.Parse(conf.ResultStoragePrefix)
 
 	if err != nil {
 		return nil, fmt.Errorf("invalid result storage prefix: %w", err)
 	}
 
+	if len(conf.ResultStorageFormat) == 0 {
+		conf.ResultStorageFormat = ResultFormatJSON
+	}
+
+	if _, err := backend.ParseFormat(string(conf.ResultStorageFormat)); err != nil {
+		return nil, fmt.Errorf("invalid result storage format: %w", err)
+	}
+
+	// fail at startup rather than on the first result
+	if _, err := Compressor(conf.ResultStorageCompression, conf.ResultStorageCompressionLevel, io.Discard); err != nil {
+		return nil, fmt.Errorf("invalid result storage compression: %w", err)
+	}
+
+	for _, t := range conf.ResultStorageTiers {
+		if _, err := url.Parse(t.Prefix); err != nil {
+			return nil, fmt.Errorf("invalid result storage prefix for tier %s: %w", t.Tier, err)
+		}
+	}
+
+	if conf.ResultPreviewMaxBytes == 0 {
+		conf.ResultPreviewMaxBytes = defaultResultPreviewMaxBytes
+	}
+
+	if len(conf.ResultStoragePathTemplate) == 0 {
+		conf.ResultStoragePathTemplate = defaultResultStoragePathTemplate
+	}
+
+	if err := validateResultStoragePathTemplate(conf.ResultStoragePathTemplate); err != nil {
+		return nil, err
+	}
+
 	connConfig, err := pgxpool.ParseConfig(conf.Dsn)
 
 	if err != nil {
@@ -74,6 +131,7 @@ func NewAsyncExecutor(
 		pool:        pool,
 		os:          objstr.FromContext(ctx),
 		queryHasher: queryHasher,
+		auditSink:   auditSink,
 	}, nil
 }
 
@@ -81,6 +139,10 @@ func (aex *AsyncExecutor) GetQueryHasher() query_hasher.QueryHasher {
 	return aex.queryHasher
 }
 
+func (aex *AsyncExecutor) GetAuditSink() audit.Sink {
+	return aex.auditSink
+}
+
 func (aex *AsyncExecutor) GetById(ctx context.Context, id int64) (*Execution, error) {
 	rows, err := queries.Query(ctx, aex.pool, "get_by_id.sql", pgx.NamedArgs{"id": id})
 
@@ -102,17 +164,76 @@ func (aex *AsyncExecutor) GetById(ctx context.Context, id int64) (*Execution, er
 }
 
 func (aex *AsyncExecutor) GetResultReader(ctx context.Context, ex *Execution) (io.ReadCloser, error) {
-	if ex == nil || len(ex.Result.StoragePath) == 0 {
+	if ex == nil || ex.Result == nil || len(ex.Result.StoragePath) == 0 {
 		return nil, nil
 	}
 
-	resUrl, _, err := aex.buildResultURL(ex)
+	resUrl, err := aex.checkedResultURL(ctx, ex)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return aex.os.Reader(ctx, resUrl)
+	r, err := aex.os.Reader(ctx, resUrl)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// results written before checksums were recorded cannot be verified
+	if len(ex.Result.StorageSha256) == 0 {
+		return r, nil
+	}
+
+	return &verifyingReader{
+		r:        r,
+		hash:     sha256.New(),
+		size:     ex.Result.StorageSize,
+		expected: ex.Result.StorageSha256,
+	}, nil
+}
+
+// GetResultReaderAt opens the stored result for ranged reads, which cannot be checked against its
+// SHA-256; only its size is verified.
+func (aex *AsyncExecutor) GetResultReaderAt(ctx context.Context, ex *Execution) (types.ReaderAt, error) {
+	if ex == nil || ex.Result == nil || len(ex.Result.StoragePath) == 0 {
+		return nil, nil
+	}
+
+	resUrl, err := aex.checkedResultURL(ctx, ex)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return aex.os.ReaderAt(ctx, resUrl)
+}
+
+// checkedResultURL returns the URL of a stored result after checking the stored object size, so that
+// a mismatch is reported before anything is sent to the client.
+func (aex *AsyncExecutor) checkedResultURL(ctx context.Context, ex *Execution) (*url.URL, error) {
+	resUrl, err := aex.ResultURL(ex)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// results written before checksums were recorded cannot be verified
+	if len(ex.Result.StorageSha256) == 0 {
+		return resUrl, nil
+	}
+
+	md, err := aex.os.ReadMetadata(ctx, resUrl)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(md.Size) != ex.Result.StorageSize {
+		return nil, fmt.Errorf("%w: stored object is %d bytes, expected %d", ErrResultCorrupted, md.Size, ex.Result.StorageSize)
+	}
+
+	return resUrl, nil
 }
 
 type ListByQueryIdOptions struct {
@@ -167,6 +288,10 @@ type CreateOptions struct {
 	Tier                string
 	CancelOtherVersions bool
 	Secrets             map[string]string
+	// Ttl is the result retention once the execution completes, GC defaults apply when nil
+	Ttl *time.Duration
+	// Timeout bounds the duration of the query once picked by a worker, unbounded when nil
+	Timeout *time.Duration
 }
 
 func (aex *AsyncExecutor) Create(
@@ -205,6 +330,11 @@ func (aex *AsyncExecutor) Create(
 
 	defer tx.Rollback(context.Background())
 
+	// taken before the query id lock, in the same order by every creation
+	if err := checkInFlight(ctx, tx, identity); err != nil {
+		return nil, err
+	}
+
 	_, err = queries.Exec(ctx, tx, "cancel_other_versions_advisory_lock.sql", pgx.NamedArgs{
 		"key": fnv1aHashInt64Sum(opts.QueryId),
 	})
@@ -213,8 +343,11 @@ func (aex *AsyncExecutor) Create(
 		return nil, err
 	}
 
+	var canceled []Execution
+
 	if opts.CancelOtherVersions {
-		_, err = queries.Exec(ctx, tx, "cancel_other_versions.sql", pgx.NamedArgs{
+		rows, err := queries.Query(ctx, tx, "cancel_other_versions.sql", pgx.NamedArgs{
+			"actor":      identity,
 			"query_id":   opts.QueryId,
 			"query_hash": queryHash,
 		})
@@ -222,6 +355,12 @@ func (aex *AsyncExecutor) Create(
 		if err != nil {
 			return nil, err
 		}
+
+		canceled, err = pgx.CollectRows(rows, pgx.RowToStructByName[Execution])
+
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	rows, err := queries.Query(ctx, tx, "create.sql", pgx.NamedArgs{
@@ -231,6 +370,8 @@ func (aex *AsyncExecutor) Create(
 		"query":      query,
 		"tier":       opts.Tier,
 		"secrets":    secrets,
+		"ttl":        opts.Ttl,
+		"timeout":    opts.Timeout,
 	})
 
 	if err != nil {
@@ -243,7 +384,15 @@ func (aex *AsyncExecutor) Create(
 		return nil, err
 	}
 
-	return &ex, tx.Commit(context.Background())
+	if err := tx.Commit(context.Background()); err != nil {
+		return nil, err
+	}
+
+	for _, canceledEx := range canceled {
+		aex.auditExecution(&canceledEx)
+	}
+
+	return &ex, nil
 }
 
 func (aex *AsyncExecutor) Close() error {
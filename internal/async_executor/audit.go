@@ -0,0 +1,35 @@
+package async_executor
+
+import (
+	"context"
+
+	"github.com/agnosticeng/agp/internal/audit"
+	"github.com/agnosticeng/agp/internal/utils"
+)
+
+func (aex *AsyncExecutor) auditExecution(ex *Execution) {
+	var ev = audit.Event{
+		Source:      audit.SourceAsync,
+		QuotaKey:    ex.CreatedBy,
+		Tier:        ex.Tier,
+		ExecutionId: &ex.Id,
+		QueryHash:   ex.QueryHash,
+		Query:       ex.Query,
+		Error:       utils.Deref(ex.Error),
+		StartedAt:   utils.DerefOr(ex.PickedAt, ex.CreatedAt),
+		CompletedAt: ex.CompletedAt,
+	}
+
+	switch ex.Status {
+	case StatusSucceeded:
+		ev.Outcome = audit.OutcomeSucceeded
+	case StatusCanceled:
+		ev.Outcome = audit.OutcomeCanceled
+	default:
+		ev.Outcome = audit.OutcomeFailed
+	}
+
+	if err := aex.auditSink.Write(context.Background(), ev); err != nil {
+		aex.logger.Error("failed to write audit event", "error", err.Error(), "execution_id", ex.Id)
+	}
+}
@@ -25,19 +25,23 @@ func (aex *AsyncExecutor) FailDead(ctx context.Context, identity string, opts Fa
 		identity,
 		opts.LeaseDuration,
 		func() error {
-			rows, err := queries.Query(ctx, aex.pool, "fail_dead.sql", nil)
+			rows, err := queries.Query(ctx, aex.pool, "fail_dead.sql", pgx.NamedArgs{"actor": identity})
 
 			if err != nil {
 				return err
 			}
 
-			count, err := countRows(rows)
+			exs, err := pgx.CollectRows(rows, pgx.RowToStructByName[Execution])
 
 			if err != nil {
 				return err
 			}
 
-			slogctx.FromCtx(ctx).Info("run", "count", count)
+			for _, ex := range exs {
+				aex.auditExecution(&ex)
+			}
+
+			slogctx.FromCtx(ctx).Info("run", "count", len(exs))
 			return err
 		},
 	)
@@ -79,6 +83,7 @@ func (aex *AsyncExecutor) GCMark(ctx context.Context, identity string, opts GCMa
 		opts.LeaseDuration,
 		func() error {
 			rows, err := queries.Query(ctx, aex.pool, "gc_mark_expired.sql", pgx.NamedArgs{
+				"actor":                identity,
 				"limit":                opts.Limit,
 				"canceled_expiration":  opts.CanceledExpiration,
 				"failed_expiration":    opts.FailedExpiration,
@@ -140,7 +145,7 @@ func (aex *AsyncExecutor) GCCleanup(ctx context.Context, identity string, opts G
 					return ex.Id, nil
 				}
 
-				u, _, err := aex.buildResultURL(ex)
+				u, err := aex.ResultURL(ex)
 
 				if err != nil {
 					return 0, err
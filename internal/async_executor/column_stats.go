@@ -0,0 +1,372 @@
+package async_executor
+
+import (
+	"cmp"
+	"encoding/json"
+	"hash/maphash"
+	"math"
+	"math/big"
+	"math/bits"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agnosticeng/agp/internal/backend"
+)
+
+const (
+	// hllPrecision gives 2^12 registers per column, for a ~1.6% distinct count standard error
+	hllPrecision = 12
+	// topValuesMaxDistinct is the cardinality above which a string column is not considered low-cardinality
+	topValuesMaxDistinct = 1000
+	topValuesCount       = 10
+)
+
+type ValueCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+type ColumnStats struct {
+	Name      string `json:"name"`
+	NullCount int64  `json:"null_count"`
+	// Min and Max are set for numbers, strings and times
+	Min json.RawMessage `json:"min,omitempty"`
+	Max json.RawMessage `json:"max,omitempty"`
+	// DistinctCount is approximate, it ignores nulls
+	DistinctCount int64 `json:"distinct_count"`
+	// TopValues is set for string columns with at most 1000 distinct values
+	TopValues []ValueCount `json:"top_values,omitempty"`
+}
+
+// columnStatsCollector computes the statistics of every column of a result as its rows are read,
+// without holding them.
+type columnStatsCollector struct {
+	seed maphash.Seed
+	accs []*columnStatsAccumulator
+}
+
+func newColumnStatsCollector() *columnStatsCollector {
+	return &columnStatsCollector{seed: maphash.MakeSeed()}
+}
+
+func (c *columnStatsCollector) init(meta backend.Schema) {
+	if c.accs != nil {
+		return
+	}
+
+	c.accs = make([]*columnStatsAccumulator, len(meta))
+
+	for i, column := range meta {
+		c.accs[i] = newColumnStatsAccumulator(c.seed, isNumericType(column.Type))
+	}
+}
+
+func (c *columnStatsCollector) add(meta backend.Schema, row backend.Row) {
+	c.init(meta)
+
+	for i, v := range row {
+		if i < len(c.accs) {
+			c.accs[i].add(v)
+		}
+	}
+}
+
+func (c *columnStatsCollector) stats(meta backend.Schema) []ColumnStats {
+	c.init(meta)
+
+	var stats = make([]ColumnStats, len(c.accs))
+
+	for i, acc := range c.accs {
+		stats[i] = acc.stats(meta[i].Name)
+	}
+
+	return stats
+}
+
+type valueKind int
+
+const (
+	kindNone valueKind = iota
+	kindInt
+	kindUint
+	kindFloat
+	// kindNumber values are exact decimal numbers, e.g. json.Number or the quoted big integers
+	// of encoded ClickHouse results
+	kindNumber
+	kindString
+	kindTime
+	// kindOther values are hashed for distinct counts but not ordered
+	kindOther
+)
+
+type scalar struct {
+	kind valueKind
+	i    int64
+	u    uint64
+	f    float64
+	n    *big.Rat
+	s    string
+	t    time.Time
+	// quoted numbers are rendered as strings, as they were in the result
+	quoted bool
+}
+
+func (a scalar) less(b scalar) bool {
+	switch a.kind {
+	case kindInt:
+		return a.i < b.i
+	case kindUint:
+		return a.u < b.u
+	case kindFloat:
+		return a.f < b.f
+	case kindNumber:
+		return a.n.Cmp(b.n) < 0
+	case kindString:
+		return a.s < b.s
+	case kindTime:
+		return a.t.Before(b.t)
+	default:
+		return false
+	}
+}
+
+func (a scalar) json() json.RawMessage {
+	var js []byte
+
+	switch a.kind {
+	case kindInt:
+		js = strconv.AppendInt(nil, a.i, 10)
+	case kindUint:
+		js = strconv.AppendUint(nil, a.u, 10)
+	case kindFloat:
+		js, _ = json.Marshal(a.f)
+	case kindNumber:
+		if a.quoted {
+			js, _ = json.Marshal(a.s)
+		} else {
+			js = []byte(a.s)
+		}
+	case kindString:
+		js, _ = json.Marshal(a.s)
+	case kindTime:
+		js, _ = json.Marshal(a.t)
+	}
+
+	return js
+}
+
+// key is the representation of the value fed to the distinct count sketch.
+func (a scalar) key() string {
+	switch a.kind {
+	case kindInt:
+		return strconv.FormatInt(a.i, 10)
+	case kindUint:
+		return strconv.FormatUint(a.u, 10)
+	case kindFloat:
+		return strconv.FormatFloat(a.f, 'g', -1, 64)
+	case kindNumber:
+		return a.n.RatString()
+	case kindTime:
+		return a.t.Format(time.RFC3339Nano)
+	default:
+		return a.s
+	}
+}
+
+var jsonNumberType = reflect.TypeOf(json.Number(""))
+
+// isNumericType tells whether strings of a column of the given ClickHouse type are numbers,
+// e.g. Nullable(UInt64) values encoded as strings.
+func isNumericType(typ string) bool {
+	for _, wrapper := range []string{"Nullable(", "LowCardinality("} {
+		typ = strings.TrimSuffix(strings.TrimPrefix(typ, wrapper), ")")
+	}
+
+	for _, prefix := range []string{"Int", "UInt", "Float", "BFloat", "Decimal"} {
+		if strings.HasPrefix(typ, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// toNumber parses the text of an exact number, ok is false when it is not one.
+func toNumber(s string, quoted bool) (scalar, bool) {
+	var n, ok = new(big.Rat).SetString(s)
+
+	if !ok {
+		return scalar{}, false
+	}
+
+	return scalar{kind: kindNumber, n: n, s: s, quoted: quoted}, true
+}
+
+// toScalar dereferences the pointers backends scan values into; ok is false for nulls.
+// Strings of numeric columns are treated as numbers.
+func toScalar(v any, numeric bool) (scalar, bool) {
+	var rv = reflect.ValueOf(v)
+
+	for rv.IsValid() && (rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface) {
+		if rv.IsNil() {
+			return scalar{}, false
+		}
+
+		rv = rv.Elem()
+	}
+
+	if !rv.IsValid() {
+		return scalar{}, false
+	}
+
+	if t, ok := rv.Interface().(time.Time); ok {
+		return scalar{kind: kindTime, t: t}, true
+	}
+
+	if rv.Type() == jsonNumberType {
+		if s, ok := toNumber(rv.String(), false); ok {
+			return s, true
+		}
+
+		return scalar{kind: kindOther, s: rv.String()}, true
+	}
+
+	if numeric && rv.Kind() == reflect.String {
+		if s, ok := toNumber(rv.String(), true); ok {
+			return s, true
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return scalar{kind: kindInt, i: rv.Int()}, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return scalar{kind: kindUint, u: rv.Uint()}, true
+	case reflect.Float32, reflect.Float64:
+		if math.IsNaN(rv.Float()) {
+			return scalar{kind: kindOther, s: "NaN"}, true
+		}
+
+		return scalar{kind: kindFloat, f: rv.Float()}, true
+	case reflect.String:
+		return scalar{kind: kindString, s: rv.String()}, true
+	default:
+		js, _ := json.Marshal(rv.Interface())
+		return scalar{kind: kindOther, s: string(js)}, true
+	}
+}
+
+type columnStatsAccumulator struct {
+	seed      maphash.Seed
+	numeric   bool
+	nulls     int64
+	kind      valueKind
+	ordered   bool
+	min, max  scalar
+	registers []uint8
+	counts    map[string]int64
+}
+
+func newColumnStatsAccumulator(seed maphash.Seed, numeric bool) *columnStatsAccumulator {
+	return &columnStatsAccumulator{
+		seed:      seed,
+		numeric:   numeric,
+		ordered:   true,
+		registers: make([]uint8, 1<<hllPrecision),
+		counts:    make(map[string]int64),
+	}
+}
+
+func (acc *columnStatsAccumulator) add(v any) {
+	var s, ok = toScalar(v, acc.numeric)
+
+	if !ok {
+		acc.nulls++
+		return
+	}
+
+	switch {
+	case acc.kind == kindNone:
+		acc.kind, acc.min, acc.max = s.kind, s, s
+	case acc.kind != s.kind:
+		// mixed value types cannot be ordered
+		acc.ordered = false
+	case s.less(acc.min):
+		acc.min = s
+	case acc.max.less(s):
+		acc.max = s
+	}
+
+	var (
+		key = s.key()
+		h   = maphash.String(acc.seed, key)
+		idx = h >> (64 - hllPrecision)
+		// position of the first set bit of the remaining bits, the idx bits being shifted out
+		rank = uint8(bits.LeadingZeros64(h<<hllPrecision|1<<(hllPrecision-1)) + 1)
+	)
+
+	acc.registers[idx] = max(acc.registers[idx], rank)
+
+	if s.kind == kindString && acc.counts != nil {
+		acc.counts[key]++
+
+		if len(acc.counts) > topValuesMaxDistinct {
+			acc.counts = nil
+		}
+	}
+}
+
+// distinctCount is the HyperLogLog estimate, with linear counting for small cardinalities.
+func (acc *columnStatsAccumulator) distinctCount() int64 {
+	var (
+		m     = float64(len(acc.registers))
+		sum   float64
+		zeros int
+	)
+
+	for _, r := range acc.registers {
+		sum += math.Ldexp(1, -int(r))
+
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	var estimate = 0.7213 / (1 + 1.079/m) * m * m / sum
+
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+
+	return int64(math.Round(estimate))
+}
+
+func (acc *columnStatsAccumulator) stats(name string) ColumnStats {
+	var stats = ColumnStats{
+		Name:          name,
+		NullCount:     acc.nulls,
+		DistinctCount: acc.distinctCount(),
+	}
+
+	if acc.ordered && acc.kind != kindNone && acc.kind != kindOther {
+		stats.Min = acc.min.json()
+		stats.Max = acc.max.json()
+	}
+
+	if acc.kind == kindString && acc.ordered && acc.counts != nil {
+		for value, count := range acc.counts {
+			stats.TopValues = append(stats.TopValues, ValueCount{Value: value, Count: count})
+		}
+
+		slices.SortFunc(stats.TopValues, func(a, b ValueCount) int {
+			return cmp.Or(cmp.Compare(b.Count, a.Count), strings.Compare(a.Value, b.Value))
+		})
+
+		stats.TopValues = stats.TopValues[:min(len(stats.TopValues), topValuesCount)]
+	}
+
+	return stats
+}
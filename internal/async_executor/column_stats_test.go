@@ -0,0 +1,178 @@
+package async_executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/agnosticeng/agp/internal/backend"
+)
+
+func collectColumnStats(meta backend.Schema, rows []backend.Row) []ColumnStats {
+	var c = newColumnStatsCollector()
+
+	for _, row := range rows {
+		c.add(meta, row)
+	}
+
+	return c.stats(meta)
+}
+
+func singleColumnStats(typ string, values ...any) ColumnStats {
+	var rows = make([]backend.Row, len(values))
+
+	for i, v := range values {
+		rows[i] = backend.Row{v}
+	}
+
+	return collectColumnStats(backend.Schema{{Name: "c", Type: typ}}, rows)[0]
+}
+
+func TestColumnStatsDistinctCount(t *testing.T) {
+	for _, n := range []int{0, 1, 10, 100, 10000, 200000} {
+		var values = make([]any, 0, 2*n)
+
+		// every value twice, duplicates must not be counted
+		for i := 0; i < n; i++ {
+			values = append(values, int64(i), int64(i))
+		}
+
+		var (
+			got       = singleColumnStats("Int64", values...).DistinctCount
+			tolerance = max(1, 0.05*float64(n))
+		)
+
+		if math.Abs(float64(got-int64(n))) > tolerance {
+			t.Errorf("%d distinct values: estimated %d", n, got)
+		}
+	}
+}
+
+func TestColumnStatsNulls(t *testing.T) {
+	var (
+		null  *int64
+		stats = singleColumnStats("Nullable(Int64)", nil, null, int64(3), int64(1))
+	)
+
+	if stats.NullCount != 2 {
+		t.Errorf("null count is %d, expected 2", stats.NullCount)
+	}
+
+	if string(stats.Min) != "1" || string(stats.Max) != "3" {
+		t.Errorf("min/max is %s/%s, expected 1/3", stats.Min, stats.Max)
+	}
+
+	if stats := singleColumnStats("Nullable(Int64)", nil, nil); stats.Min != nil || stats.Max != nil || stats.DistinctCount != 0 {
+		t.Errorf("expected no min, max or distinct values for a null column, got %+v", stats)
+	}
+}
+
+func TestColumnStatsMinMax(t *testing.T) {
+	var (
+		ts = time.Date(2024, 3, 5, 14, 7, 9, 0, time.UTC)
+		u  = uint64(math.MaxUint64)
+	)
+
+	var cases = []struct {
+		name     string
+		typ      string
+		values   []any
+		min, max string
+	}{
+		{name: "ints", typ: "Int32", values: []any{int32(5), int32(-2), int32(7)}, min: "-2", max: "7"},
+		{name: "pointers", typ: "UInt64", values: []any{&u, ptrTo(uint64(1))}, min: "1", max: "18446744073709551615"},
+		{name: "floats", typ: "Float64", values: []any{1.5, -0.25, math.NaN()}, min: "", max: ""},
+		{name: "float without nan", typ: "Float64", values: []any{1.5, -0.25}, min: "-0.25", max: "1.5"},
+		// big integers encoded as strings are compared as numbers and rendered as they were
+		{name: "quoted numbers", typ: "UInt64", values: []any{"9", "18446744073709551615", "10"}, min: `"9"`, max: `"18446744073709551615"`},
+		{name: "json numbers", typ: "Decimal(9, 2)", values: []any{json.Number("10.50"), json.Number("9.99")}, min: "9.99", max: "10.50"},
+		{name: "strings", typ: "String", values: []any{"b", "a", "c"}, min: `"a"`, max: `"c"`},
+		{name: "numeric looking strings", typ: "String", values: []any{"9", "10"}, min: `"10"`, max: `"9"`},
+		{name: "times", typ: "DateTime", values: []any{ts, ts.Add(-time.Hour), ts.Add(time.Hour)}, min: `"2024-03-05T13:07:09Z"`, max: `"2024-03-05T15:07:09Z"`},
+		// values of distinct kinds cannot be ordered
+		{name: "mixed kinds", typ: "Dynamic", values: []any{int64(1), "a", 2.5}, min: "", max: ""},
+		{name: "mixed numbers", typ: "Dynamic", values: []any{int64(1), uint64(2)}, min: "", max: ""},
+		{name: "others", typ: "Array(Int64)", values: []any{[]int64{1}, []int64{2}}, min: "", max: ""},
+	}
+
+	for _, c := range cases {
+		var stats = singleColumnStats(c.typ, c.values...)
+
+		if string(stats.Min) != c.min || string(stats.Max) != c.max {
+			t.Errorf("%s: min/max is %s/%s, expected %s/%s", c.name, stats.Min, stats.Max, c.min, c.max)
+		}
+	}
+}
+
+func ptrTo[T any](v T) *T {
+	return &v
+}
+
+func TestColumnStatsTopValues(t *testing.T) {
+	var values []any
+
+	// value i appears i times, "v01" to "v12"
+	for i := 1; i <= 12; i++ {
+		for j := 0; j < i; j++ {
+			values = append(values, fmt.Sprintf("v%02d", i))
+		}
+	}
+
+	// ties are broken by value
+	values = append(values, "v11b", "v11b", "v11b", "v11b", "v11b", "v11b", "v11b", "v11b", "v11b", "v11b", "v11b")
+
+	var stats = singleColumnStats("String", values...)
+
+	if len(stats.TopValues) != topValuesCount {
+		t.Fatalf("got %d top values, expected %d", len(stats.TopValues), topValuesCount)
+	}
+
+	var expected = []ValueCount{{"v12", 12}, {"v11", 11}, {"v11b", 11}, {"v10", 10}, {"v09", 9}}
+
+	for i, want := range expected {
+		if stats.TopValues[i] != want {
+			t.Errorf("top value %d is %+v, expected %+v", i, stats.TopValues[i], want)
+		}
+	}
+
+	if last := stats.TopValues[topValuesCount-1]; last != (ValueCount{"v04", 4}) {
+		t.Errorf("last top value is %+v, expected v04", last)
+	}
+}
+
+func TestColumnStatsTopValuesCutoff(t *testing.T) {
+	var values []any
+
+	for i := 0; i < topValuesMaxDistinct; i++ {
+		values = append(values, strconv.Itoa(i))
+	}
+
+	if stats := singleColumnStats("String", values...); len(stats.TopValues) != topValuesCount {
+		t.Errorf("got %d top values at the cardinality limit, expected %d", len(stats.TopValues), topValuesCount)
+	}
+
+	values = append(values, "one too many")
+
+	if stats := singleColumnStats("String", values...); stats.TopValues != nil {
+		t.Errorf("expected no top values above the cardinality limit, got %d", len(stats.TopValues))
+	}
+
+	// only string columns have top values
+	if stats := singleColumnStats("Int64", int64(1), int64(1)); stats.TopValues != nil {
+		t.Errorf("expected no top values for numbers, got %+v", stats.TopValues)
+	}
+}
+
+func TestColumnStatsSchema(t *testing.T) {
+	var (
+		meta  = backend.Schema{{Name: "a", Type: "Int64"}, {Name: "b", Type: "String"}}
+		stats = collectColumnStats(meta, nil)
+	)
+
+	if len(stats) != 2 || stats[0].Name != "a" || stats[1].Name != "b" {
+		t.Errorf("expected stats for every column of an empty result, got %+v", stats)
+	}
+}
@@ -4,19 +4,75 @@ import (
 	"compress/gzip"
 	"fmt"
 	"io"
+	"strings"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
 )
 
-func Compressor(codec ResultCompression, w io.Writer) (io.WriteCloser, error) {
+// Compressor wraps w with the given codec; level is codec specific (gzip 1-9, zstd 1-22, lz4 1-9),
+// zero selects the codec default.
+func Compressor(codec ResultCompression, level int, w io.Writer) (io.WriteCloser, error) {
 	switch codec {
 	case ResultCompressionNone:
 		return &noopWriterCloser{w}, nil
 	case ResultCompressionGZIP:
-		return gzip.NewWriter(w), nil
+		if level == 0 {
+			return gzip.NewWriter(w), nil
+		}
+
+		return gzip.NewWriterLevel(w, level)
+	case ResultCompressionZSTD:
+		if level == 0 {
+			return zstd.NewWriter(w)
+		}
+
+		return zstd.NewWriter(w, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+	case ResultCompressionLZ4:
+		var lw = lz4.NewWriter(w)
+
+		if level == 0 {
+			return lw, nil
+		}
+
+		if level < 1 || level > 9 {
+			return nil, fmt.Errorf("invalid lz4 compression level: %d", level)
+		}
+
+		if err := lw.Apply(lz4.CompressionLevelOption(lz4.CompressionLevel(1 << (8 + level)))); err != nil {
+			return nil, err
+		}
+
+		return lw, nil
+	case ResultCompressionSnappy:
+		return snappy.NewBufferedWriter(w), nil
 	default:
 		return nil, fmt.Errorf("unknown compression codec: %s", codec)
 	}
 }
 
+// compressionExtension returns the file extension suffix of a compression codec.
+func compressionExtension(codec ResultCompression) string {
+	switch codec {
+	case ResultCompressionGZIP:
+		return ".gz"
+	case ResultCompressionZSTD:
+		return ".zst"
+	case ResultCompressionLZ4:
+		return ".lz4"
+	case ResultCompressionSnappy:
+		return ".sz"
+	default:
+		return ""
+	}
+}
+
+// ContentEncoding returns the HTTP content coding of a compression codec, or an empty string for none.
+func ContentEncoding(codec ResultCompression) string {
+	return strings.ToLower(string(codec))
+}
+
 type noopWriterCloser struct {
 	io.Writer
 }
@@ -31,6 +87,18 @@ func Decompressor(codec ResultCompression, r io.Reader) (io.ReadCloser, error) {
 		return io.NopCloser(r), nil
 	case ResultCompressionGZIP:
 		return gzip.NewReader(r)
+	case ResultCompressionZSTD:
+		zr, err := zstd.NewReader(r)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return zr.IOReadCloser(), nil
+	case ResultCompressionLZ4:
+		return io.NopCloser(lz4.NewReader(r)), nil
+	case ResultCompressionSnappy:
+		return io.NopCloser(snappy.NewReader(r)), nil
 	default:
 		return nil, fmt.Errorf("unknown compression codec: %s", codec)
 	}
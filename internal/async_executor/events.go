@@ -0,0 +1,31 @@
+package async_executor
+
+import (
+	"context"
+
+	"github.com/agnosticeng/agp/internal/async_executor/queries"
+	"github.com/jackc/pgx/v5"
+	"github.com/samber/lo"
+)
+
+const maxExecutionEvents = 1000
+
+// ListEvents returns the history of an execution, from the oldest event to the most recent.
+func (aex *AsyncExecutor) ListEvents(ctx context.Context, executionId int64) ([]*ExecutionEvent, error) {
+	rows, err := queries.Query(ctx, aex.pool, "list_execution_events.sql", pgx.NamedArgs{
+		"execution_id": executionId,
+		"limit":        maxExecutionEvents,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	evs, err := pgx.CollectRows(rows, pgx.RowToStructByName[ExecutionEvent])
+
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.ToSlicePtr(evs), nil
+}
@@ -0,0 +1,93 @@
+package async_executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"slices"
+
+	"github.com/agnosticeng/agp/internal/async_executor/queries"
+	objstr_errors "github.com/agnosticeng/objstr/errors"
+	"github.com/jackc/pgx/v5"
+)
+
+// resultStorageProbe is the object whose metadata is read to check a result storage prefix, it is
+// never written.
+const resultStorageProbe = ".agp_health_check"
+
+func (aex *AsyncExecutor) Ping(ctx context.Context) error {
+	return aex.pool.Ping(ctx)
+}
+
+// PingResultStorage reads the metadata of a probe object under every result storage prefix, a missing
+// object telling that the storage is reachable; listing a large prefix can be expensive and writing on
+// every probe is costly on object stores.
+func (aex *AsyncExecutor) PingResultStorage(ctx context.Context) error {
+	var prefixes = []string{aex.conf.ResultStoragePrefix}
+
+	for _, t := range aex.conf.ResultStorageTiers {
+		if len(t.Prefix) > 0 && !slices.Contains(prefixes, t.Prefix) {
+			prefixes = append(prefixes, t.Prefix)
+		}
+	}
+
+	var errs []error
+
+	for _, prefix := range prefixes {
+		u, err := url.Parse(prefix)
+
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		u.Path = path.Join(u.Path, resultStorageProbe)
+
+		if _, err := aex.os.ReadMetadata(ctx, u); err != nil && !errors.Is(err, objstr_errors.ErrObjectNotFound) {
+			errs = append(errs, fmt.Errorf("%s: %w", prefix, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// SchemaVersion returns the migration version applied to the database, as recorded by tern.
+func (aex *AsyncExecutor) SchemaVersion(ctx context.Context, versionTable string) (int32, error) {
+	rows, err := queries.Query(ctx, aex.pool, "schema_version.sql", pgx.NamedArgs{
+		"version_table": pgx.Identifier{versionTable}.Sanitize(),
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	version, err := pgx.CollectExactlyOneRow(rows, pgx.RowTo[int32])
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, fmt.Errorf("no version found in table: %s", versionTable)
+	}
+
+	return version, err
+}
+
+func (aex *AsyncExecutor) GetLease(ctx context.Context, key string) (*Lease, error) {
+	rows, err := queries.Query(ctx, aex.pool, "get_lease.sql", pgx.NamedArgs{"key": key})
+
+	if err != nil {
+		return nil, err
+	}
+
+	lease, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[Lease])
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &lease, nil
+}
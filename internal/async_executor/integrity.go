@@ -0,0 +1,111 @@
+package async_executor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+)
+
+var ErrResultCorrupted = errors.New("result is corrupted")
+
+// hashingWriter computes the size and SHA-256 of what is written through it.
+type hashingWriter struct {
+	w    io.Writer
+	hash hash.Hash
+	n    int64
+}
+
+func newHashingWriter(w io.Writer) *hashingWriter {
+	return &hashingWriter{w: w, hash: sha256.New()}
+}
+
+func (w *hashingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.hash.Write(p[:n])
+	w.n += int64(n)
+	return n, err
+}
+
+func (w *hashingWriter) sum() string {
+	return hex.EncodeToString(w.hash.Sum(nil))
+}
+
+// verifyHoldBack is the number of final bytes of a stored result withheld until its size and SHA-256
+// are verified, so that a corrupted object is never served in full.
+const verifyHoldBack = 32 * 1024
+
+// verifyingReader checks the size and SHA-256 of the stored result once fully read, so that a
+// corrupted or truncated object fails the read instead of being served silently. The last
+// verifyHoldBack bytes are only returned once the object is verified, a reader of a corrupted
+// object never gets all of its bytes.
+type verifyingReader struct {
+	r        io.ReadCloser
+	hash     hash.Hash
+	n        int64
+	size     int64
+	expected string
+	held     []byte
+	verified bool
+	err      error
+}
+
+func (r *verifyingReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	for {
+		if r.verified {
+			if len(r.held) == 0 {
+				return 0, io.EOF
+			}
+
+			var n = copy(p, r.held)
+			r.held = r.held[n:]
+			return n, nil
+		}
+
+		n, err := r.r.Read(p)
+		r.hash.Write(p[:n])
+		r.n += int64(n)
+
+		// bytes beyond the hold back threshold are kept, the object is never served past its size
+		var (
+			threshold = max(0, r.size-verifyHoldBack)
+			release   = int(min(max(threshold-(r.n-int64(n)), 0), int64(n)))
+		)
+
+		r.held = append(r.held, p[release:n]...)
+
+		if r.n > r.size {
+			r.err = fmt.Errorf("%w: more than the %d bytes expected", ErrResultCorrupted, r.size)
+			return release, r.err
+		}
+
+		if errors.Is(err, io.EOF) {
+			if r.n != r.size {
+				r.err = fmt.Errorf("%w: read %d bytes, expected %d", ErrResultCorrupted, r.n, r.size)
+				return release, r.err
+			}
+
+			if sum := hex.EncodeToString(r.hash.Sum(nil)); sum != r.expected {
+				r.err = fmt.Errorf("%w: SHA-256 is %s, expected %s", ErrResultCorrupted, sum, r.expected)
+				return release, r.err
+			}
+
+			r.verified = true
+			err = nil
+		}
+
+		if release > 0 || err != nil {
+			return release, err
+		}
+	}
+}
+
+func (r *verifyingReader) Close() error {
+	return r.r.Close()
+}
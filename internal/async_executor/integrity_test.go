@@ -0,0 +1,89 @@
+package async_executor
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func sha256Hex(b []byte) string {
+	var sum = sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func newTestVerifyingReader(stored []byte, size int64, expected string) *verifyingReader {
+	return &verifyingReader{
+		// one byte at a time exercises the hold back boundary on every read
+		r:        io.NopCloser(iotest.OneByteReader(bytes.NewReader(stored))),
+		hash:     sha256.New(),
+		size:     size,
+		expected: expected,
+	}
+}
+
+func TestVerifyingReader(t *testing.T) {
+	var (
+		small = []byte("hello")
+		large = bytes.Repeat([]byte("0123456789"), verifyHoldBack/5)
+	)
+
+	for name, content := range map[string][]byte{"empty": {}, "small": small, "large": large} {
+		var r = newTestVerifyingReader(content, int64(len(content)), sha256Hex(content))
+
+		got, err := io.ReadAll(r)
+
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		if !bytes.Equal(got, content) {
+			t.Errorf("%s: read %d bytes, expected %d", name, len(got), len(content))
+		}
+	}
+}
+
+func TestVerifyingReaderCorrupted(t *testing.T) {
+	var (
+		content   = bytes.Repeat([]byte("0123456789"), verifyHoldBack/5)
+		size      = int64(len(content))
+		sum       = sha256Hex(content)
+		corrupted = bytes.Clone(content)
+	)
+
+	corrupted[len(corrupted)-1] ^= 1
+
+	var cases = []struct {
+		name   string
+		stored []byte
+		size   int64
+		sum    string
+	}{
+		{name: "truncated", stored: content[:len(content)-1], size: size, sum: sum},
+		{name: "oversized", stored: append(bytes.Clone(content), '!'), size: size, sum: sum},
+		{name: "wrong hash", stored: corrupted, size: size, sum: sum},
+		{name: "small wrong hash", stored: []byte("hellO"), size: 5, sum: sha256Hex([]byte("hello"))},
+	}
+
+	for _, c := range cases {
+		var r = newTestVerifyingReader(c.stored, c.size, c.sum)
+
+		got, err := io.ReadAll(r)
+
+		if !errors.Is(err, ErrResultCorrupted) {
+			t.Errorf("%s: expected ErrResultCorrupted, got %v", c.name, err)
+		}
+
+		// the withheld bytes must never reach the reader
+		if want := max(0, c.size-verifyHoldBack); int64(len(got)) > want {
+			t.Errorf("%s: %d bytes were returned, expected at most %d", c.name, len(got), want)
+		}
+
+		if _, err := r.Read(make([]byte, 16)); !errors.Is(err, ErrResultCorrupted) {
+			t.Errorf("%s: expected the error to be sticky, got %v", c.name, err)
+		}
+	}
+}
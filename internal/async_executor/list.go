@@ -0,0 +1,172 @@
+package async_executor
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agnosticeng/agp/internal/async_executor/queries"
+	"github.com/jackc/pgx/v5"
+	"github.com/samber/lo"
+)
+
+// ExecutionFilter selects executions by attributes, empty fields match any execution.
+type ExecutionFilter struct {
+	Ids           []int64
+	Statuses      []Status
+	Tiers         []string
+	PickedBy      string
+	CreatedBy     string
+	QueryIdPrefix string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+}
+
+func (f ExecutionFilter) IsEmpty() bool {
+	return len(f.Ids) == 0 &&
+		len(f.Statuses) == 0 &&
+		len(f.Tiers) == 0 &&
+		len(f.PickedBy) == 0 &&
+		len(f.CreatedBy) == 0 &&
+		len(f.QueryIdPrefix) == 0 &&
+		f.CreatedAfter == nil &&
+		f.CreatedBefore == nil
+}
+
+func (f ExecutionFilter) args() pgx.NamedArgs {
+	var args = pgx.NamedArgs{
+		"ids":            f.Ids,
+		"statuses":       f.Statuses,
+		"tiers":          f.Tiers,
+		"picked_by":      f.PickedBy,
+		"created_by":     f.CreatedBy,
+		"query_id_like":  "",
+		"created_after":  f.CreatedAfter,
+		"created_before": f.CreatedBefore,
+	}
+
+	if len(f.QueryIdPrefix) > 0 {
+		args["query_id_like"] = likeEscaper.Replace(f.QueryIdPrefix) + "%"
+	}
+
+	return args
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// ExecutionCursor is the position of the last execution of a page, executions are listed
+// from the most recent to the oldest.
+type ExecutionCursor struct {
+	CreatedAt time.Time
+	Id        int64
+}
+
+// Encode returns the opaque representation of the cursor handed to API clients.
+func (c ExecutionCursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString(fmt.Appendf(nil, "%d:%d", c.CreatedAt.UnixMicro(), c.Id))
+}
+
+func DecodeExecutionCursor(s string) (*ExecutionCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	createdAtStr, idStr, found := strings.Cut(string(b), ":")
+
+	if !found {
+		return nil, fmt.Errorf("invalid cursor: missing separator")
+	}
+
+	createdAt, err := strconv.ParseInt(createdAtStr, 10, 64)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	return &ExecutionCursor{CreatedAt: time.UnixMicro(createdAt), Id: id}, nil
+}
+
+type ListExecutionsOptions struct {
+	ExecutionFilter
+	After      *ExecutionCursor
+	Limit      int
+	TotalCount bool
+}
+
+type ExecutionPage struct {
+	Executions []*Execution
+	Next       *ExecutionCursor
+	TotalCount *int64
+}
+
+// ListExecutions returns a page of executions, with the cursor of the next page if there is one,
+// and the number of executions matching the filter when requested.
+func (aex *AsyncExecutor) ListExecutions(ctx context.Context, opts ListExecutionsOptions) (*ExecutionPage, error) {
+	if opts.Limit <= 0 {
+		opts.Limit = 100
+	} else {
+		opts.Limit = min(opts.Limit, 1000)
+	}
+
+	var args = opts.args()
+
+	// one more row is fetched to know whether there is a next page
+	args["limit"] = opts.Limit + 1
+	args["after_id"] = int64(0)
+	args["after_created_at"] = time.Time{}
+
+	if opts.After != nil {
+		args["after_id"] = opts.After.Id
+		args["after_created_at"] = opts.After.CreatedAt
+	}
+
+	rows, err := queries.Query(ctx, aex.pool, "list_executions.sql", args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	exs, err := pgx.CollectRows(rows, pgx.RowToStructByName[Execution])
+
+	if err != nil {
+		return nil, err
+	}
+
+	var page ExecutionPage
+
+	if len(exs) > opts.Limit {
+		exs = exs[:opts.Limit]
+		page.Next = &ExecutionCursor{CreatedAt: exs[len(exs)-1].CreatedAt, Id: exs[len(exs)-1].Id}
+	}
+
+	page.Executions = lo.ToSlicePtr(exs)
+
+	if opts.TotalCount {
+		rows, err := queries.Query(ctx, aex.pool, "count_executions.sql", opts.args())
+
+		if err != nil {
+			return nil, err
+		}
+
+		count, err := pgx.CollectExactlyOneRow(rows, pgx.RowTo[int64])
+
+		if err != nil {
+			return nil, err
+		}
+
+		page.TotalCount = &count
+	}
+
+	return &page, nil
+}
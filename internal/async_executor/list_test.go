@@ -0,0 +1,64 @@
+package async_executor
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+func TestExecutionCursorRoundTrip(t *testing.T) {
+	var cursors = []ExecutionCursor{
+		{CreatedAt: time.Date(2024, 3, 5, 14, 7, 9, 123456000, time.UTC), Id: 42},
+		{CreatedAt: time.UnixMicro(0), Id: 1},
+		{CreatedAt: time.Date(1969, 12, 31, 23, 59, 59, 0, time.UTC), Id: 9223372036854775807},
+	}
+
+	for _, c := range cursors {
+		decoded, err := DecodeExecutionCursor(c.Encode())
+
+		if err != nil {
+			t.Fatalf("%v: %v", c, err)
+		}
+
+		if !decoded.CreatedAt.Equal(c.CreatedAt) || decoded.Id != c.Id {
+			t.Errorf("got %v, want %v", decoded, c)
+		}
+	}
+}
+
+func TestExecutionCursorTruncatesToMicroseconds(t *testing.T) {
+	var c = ExecutionCursor{CreatedAt: time.Date(2024, 3, 5, 14, 7, 9, 123456789, time.UTC), Id: 1}
+
+	decoded, err := DecodeExecutionCursor(c.Encode())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := c.CreatedAt.Truncate(time.Microsecond); !decoded.CreatedAt.Equal(want) {
+		t.Errorf("got %v, want %v", decoded.CreatedAt, want)
+	}
+}
+
+func TestDecodeExecutionCursorInvalid(t *testing.T) {
+	var encode = func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	var cases = map[string]string{
+		"empty":              "",
+		"not base64":         "!!!",
+		"padded base64":      base64.URLEncoding.EncodeToString([]byte("1:23")),
+		"missing separator":  encode("12"),
+		"missing id":         encode("12:"),
+		"missing created_at": encode(":12"),
+		"trailing data":      encode("12:34junk"),
+		"extra field":        encode("12:34:56"),
+		"not a number":       encode("a:b"),
+		"overflow":           encode("1:9223372036854775808"),
+	}
+
+	for name, s := range cases {
+		if c, err := DecodeExecutionCursor(s); err == nil {
+			t.Errorf("%s: expected an error, got %v", name, c)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package async_executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/agnosticeng/agp/internal/async_executor/queries"
+	"github.com/agnosticeng/agp/internal/backend"
+	"github.com/jackc/pgx/v5"
+)
+
+const defaultResultPreviewMaxBytes = 64 * 1024
+
+// ResultPreview holds the first rows of a successful execution result, Complete is set when it
+// contains all of them.
+type ResultPreview struct {
+	ExecutionId int64
+	Data        json.RawMessage
+	Complete    bool
+}
+
+// previewBuilder encodes up to ResultPreviewRows rows as a JSON array of objects as they are read,
+// stopping before exceeding ResultPreviewMaxBytes.
+type previewBuilder struct {
+	maxRows  int
+	maxBytes int
+	buf      bytes.Buffer
+	count    int
+	full     bool
+}
+
+func newPreviewBuilder(maxRows int, maxBytes int) *previewBuilder {
+	var b = previewBuilder{maxRows: maxRows, maxBytes: maxBytes}
+	b.buf.WriteByte('[')
+	return &b
+}
+
+func (b *previewBuilder) add(meta backend.Schema, row backend.Row) error {
+	if b.full || b.count >= b.maxRows {
+		b.full = true
+		return nil
+	}
+
+	js, err := json.Marshal(backend.FormatRow(meta, row, backend.FormatJSON))
+
+	if err != nil {
+		return err
+	}
+
+	if b.buf.Len()+len(js)+2 > b.maxBytes {
+		b.full = true
+		return nil
+	}
+
+	if b.count > 0 {
+		b.buf.WriteByte(',')
+	}
+
+	b.buf.Write(js)
+	b.count++
+	return nil
+}
+
+// build returns the preview and whether it holds all the rows of the result, or nil when previews
+// are disabled.
+func (b *previewBuilder) build(rows int64) (json.RawMessage, bool) {
+	if b.maxRows <= 0 {
+		return nil, false
+	}
+
+	b.buf.WriteByte(']')
+	return b.buf.Bytes(), int64(b.count) == rows
+}
+
+// GetPreviews returns the result previews of the given executions, indexed by execution id; executions
+// without a preview are omitted.
+func (aex *AsyncExecutor) GetPreviews(ctx context.Context, ids []int64) (map[int64]*ResultPreview, error) {
+	var res = make(map[int64]*ResultPreview)
+
+	if len(ids) == 0 {
+		return res, nil
+	}
+
+	rows, err := queries.Query(ctx, aex.pool, "list_previews.sql", pgx.NamedArgs{"ids": ids})
+
+	if err != nil {
+		return nil, err
+	}
+
+	previews, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[ResultPreview])
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, preview := range previews {
+		res[preview.ExecutionId] = preview
+	}
+
+	return res, nil
+}
@@ -0,0 +1,13 @@
+with ex as (
+    update agp_execution
+    set 
+        status = 'CANCELED',
+        secrets = null
+    where status in ('PENDING', 'RUNNING')
+    {{template "execution_filter.sql" .}}
+    returning *
+), ev as (
+    insert into agp_execution_event (execution_id, type, actor, status, data)
+    select id, 'CANCELED', @actor, status, jsonb_build_object('reason', 'admin') from ex
+)
+select * from ex
@@ -1,7 +1,14 @@
-update agp_execution
-set 
-    status = 'CANCELED',
-    secrets = null
-where query_id = @query_id
-and query_hash <> @query_hash
-and status in ('PENDING', 'RUNNING')
\ No newline at end of file
+with ex as (
+    update agp_execution
+    set 
+        status = 'CANCELED',
+        secrets = null
+    where query_id = @query_id
+    and query_hash <> @query_hash
+    and status in ('PENDING', 'RUNNING')
+    returning *
+), ev as (
+    insert into agp_execution_event (execution_id, type, actor, status, data)
+    select id, 'CANCELED', @actor, status, jsonb_build_object('reason', 'new query version') from ex
+)
+select * from ex
@@ -1,10 +1,30 @@
-update agp_execution
-set 
-    status = @status,
-    result = @result,
-    error = @error,
-    completed_at = now(),
-    secrets = null
-where id = @id
-and picked_by = @picked_by
-returning *
\ No newline at end of file
+with ex as (
+    update agp_execution
+    set 
+        status = @status,
+        result = @result,
+        error = @error,
+        completed_at = now(),
+        expires_at = now() + ttl,
+        secrets = null
+    where id = @id
+    and picked_by = @picked_by
+    and attempt = @attempt
+    and status = 'RUNNING'
+    returning *
+), ev as (
+    insert into agp_execution_event (execution_id, type, actor, status, data)
+    select 
+        id, 
+        'COMPLETED', 
+        @picked_by, 
+        status, 
+        case when error <> '' then jsonb_build_object('error', error) end
+    from ex
+), pv as (
+    insert into agp_execution_preview (execution_id, data, complete)
+    select id, @preview::jsonb, @preview_complete
+    from ex
+    where @preview::jsonb is not null
+)
+select * from ex
@@ -0,0 +1,10 @@
+insert into agp_concurrency_slot (key, owner, expires_at)
+select @key, @owner, now() + @ttl
+where (
+    select 
+        count(*) 
+    from agp_concurrency_slot 
+    where key = @key 
+    and expires_at >= now()
+) < @limit
+returning *
@@ -0,0 +1,3 @@
+delete from agp_concurrency_slot
+where key = @key
+and expires_at < now()
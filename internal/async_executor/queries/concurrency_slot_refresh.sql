@@ -0,0 +1,4 @@
+update agp_concurrency_slot
+set expires_at = now() + @ttl
+where id = @id
+returning *
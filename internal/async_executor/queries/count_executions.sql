@@ -0,0 +1,5 @@
+select 
+    count(*)
+from agp_execution
+where true
+{{template "execution_filter.sql" .}}
\ No newline at end of file
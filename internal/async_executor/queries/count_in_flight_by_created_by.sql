@@ -0,0 +1,5 @@
+select 
+    count(*)
+from agp_execution
+where created_by = @created_by
+and status in ('PENDING', 'RUNNING')
@@ -1,22 +1,49 @@
-insert into agp_execution (
-    created_by,
-    query_id,
-    query_hash,
-    query,
-    tier,
-    secrets,
-    status
-) values (
-    @created_by,
-    @query_id,
-    @query_hash,
-    @query,
-    @tier,
-    @secrets,
-    'PENDING'
+with ex as (
+    insert into agp_execution (
+        created_by,
+        query_id,
+        query_hash,
+        query,
+        tier,
+        secrets,
+        ttl,
+        timeout,
+        status
+    ) values (
+        @created_by,
+        @query_id,
+        @query_hash,
+        @query,
+        @tier,
+        @secrets,
+        @ttl::interval,
+        @timeout::interval,
+        'PENDING'
+    )
+    on conflict (query_id)
+    where status in ('PENDING', 'RUNNING')
+    do update 
+        set 
+            collapsed_counter = agp_execution.collapsed_counter + 1,
+            -- collapsed callers share the result, keep it for the longest TTL requested,
+            -- a null TTL defers to the GC defaults
+            ttl = case 
+                when agp_execution.ttl is null or excluded.ttl is null then null 
+                else greatest(agp_execution.ttl, excluded.ttl) 
+            end,
+            -- and for the longest timeout, a null timeout being unbounded
+            timeout = case 
+                when agp_execution.timeout is null or excluded.timeout is null then null 
+                else greatest(agp_execution.timeout, excluded.timeout) 
+            end
+    returning *
+), ev as (
+    insert into agp_execution_event (execution_id, type, actor, status)
+    select 
+        id, 
+        case when collapsed_counter = 0 then 'CREATED' else 'COLLAPSED' end,
+        @created_by,
+        status
+    from ex
 )
-on conflict (query_id)
-where status in ('PENDING', 'RUNNING')
-do update 
-    set collapsed_counter = agp_execution.collapsed_counter + 1
-returning *
\ No newline at end of file
+select * from ex
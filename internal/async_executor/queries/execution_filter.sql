@@ -0,0 +1,24 @@
+{{if .ids}}
+and id = any(@ids)
+{{end}}
+{{if .statuses}}
+and status = any(@statuses)
+{{end}}
+{{if .tiers}}
+and tier = any(@tiers)
+{{end}}
+{{if ne .picked_by ""}}
+and picked_by = @picked_by
+{{end}}
+{{if ne .created_by ""}}
+and created_by = @created_by
+{{end}}
+{{if ne .query_id_like ""}}
+and query_id like @query_id_like
+{{end}}
+{{if .created_after}}
+and created_at >= @created_after
+{{end}}
+{{if .created_before}}
+and created_at < @created_before
+{{end}}
@@ -1,7 +1,13 @@
-update agp_execution
-set
-    status = 'FAILED',
-    error = 'Dead worker'
-where status = 'RUNNING'
-and dead_at < now()
-returning id
\ No newline at end of file
+with ex as (
+    update agp_execution
+    set
+        status = 'FAILED',
+        error = 'Dead worker'
+    where status = 'RUNNING'
+    and dead_at < now()
+    returning *
+), ev as (
+    insert into agp_execution_event (execution_id, type, actor, status, data)
+    select id, 'DEAD', @actor, status, jsonb_build_object('picked_by', picked_by) from ex
+)
+select * from ex
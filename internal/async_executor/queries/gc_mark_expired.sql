@@ -1,13 +1,29 @@
-update agp_execution
-set
-    status = 'EXPIRED'
-where id in (
-    select 
-        id
-    from agp_execution
-    where (status = 'CANCELED'  and (age(now(), created_at)   > @canceled_expiration))
-    or    (status = 'FAILED'    and (age(now(), completed_at) > @failed_expiration))
-    or    (status = 'SUCCEEDED' and (age(now(), completed_at) > @succeeded_expiration))
-    limit @limit
+with ex as (
+    update agp_execution
+    set
+        status = 'EXPIRED'
+    where id in (
+        select 
+            id
+        from agp_execution
+        where status in ('CANCELED', 'FAILED', 'SUCCEEDED')
+        and not pinned
+        and (
+            (expires_at is not null and expires_at < now())
+            or (
+                expires_at is null 
+                and (
+                    (status = 'CANCELED'  and (age(now(), created_at)   > @canceled_expiration))
+                    or (status = 'FAILED'    and (age(now(), completed_at) > @failed_expiration))
+                    or (status = 'SUCCEEDED' and (age(now(), completed_at) > @succeeded_expiration))
+                )
+            )
+        )
+        limit @limit
+    )
+    returning id, status
+), ev as (
+    insert into agp_execution_event (execution_id, type, actor, status)
+    select id, 'EXPIRED', @actor, status from ex
 )
-returning id
\ No newline at end of file
+select id from ex
@@ -0,0 +1,2 @@
+select * from agp_lease
+where key = @key
\ No newline at end of file
@@ -1,7 +1,21 @@
-update agp_execution
-set 
-    dead_at = now() + @max_heartbeat_interval,
-    progress = @progress
-where id = @id
-and picked_by = @picked_by
-returning *
\ No newline at end of file
+with ex as (
+    update agp_execution
+    set 
+        dead_at = now() + @max_heartbeat_interval,
+        progress = @progress
+    where id = @id
+    and picked_by = @picked_by
+    and attempt = @attempt
+    returning *
+), ev as (
+    insert into agp_execution_event (execution_id, type, actor, status, data)
+    select id, 'PROGRESS', @picked_by, status, progress from ex
+    where not exists (
+        select 1
+        from agp_execution_event
+        where execution_id = ex.id
+        and type = 'PROGRESS'
+        and created_at > now() - @progress_event_interval::interval
+    )
+)
+select * from ex
@@ -0,0 +1,6 @@
+select 
+    *
+from agp_execution_event
+where execution_id = @execution_id
+order by id asc
+limit {{.limit}}
\ No newline at end of file
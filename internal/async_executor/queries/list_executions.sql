@@ -0,0 +1,10 @@
+select 
+    *
+from agp_execution
+where true
+{{template "execution_filter.sql" .}}
+{{if .after_id}}
+and (created_at, id) < (@after_created_at, @after_id)
+{{end}}
+order by created_at desc, id desc
+limit {{.limit}}
@@ -0,0 +1,2 @@
+select * from agp_lease
+order by key
\ No newline at end of file
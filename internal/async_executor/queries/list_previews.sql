@@ -0,0 +1,4 @@
+select
+    *
+from agp_execution_preview
+where execution_id = any(@ids)
@@ -1,17 +1,24 @@
-update agp_execution
-set 
-	status = 'RUNNING',
-	picked_at = now(),
-	picked_by = @picked_by,
-	dead_at = now() + @max_heartbeat_interval
-where id = (
-	select 
-		id
-	from agp_execution
-	where tier = @tier
-	and status = 'PENDING'
-	order by created_at asc
-	for update skip locked
-	limit 1
+with ex as (
+    update agp_execution
+    set 
+        status = 'RUNNING',
+        picked_at = now(),
+        picked_by = @picked_by,
+        attempt = attempt + 1,
+        dead_at = now() + @max_heartbeat_interval
+    where id = (
+        select 
+            id
+        from agp_execution
+        where tier = @tier
+        and status = 'PENDING'
+        order by created_at asc
+        for update skip locked
+        limit 1
+    )
+    returning *
+), ev as (
+    insert into agp_execution_event (execution_id, type, actor, status)
+    select id, 'PICKED', @picked_by, status from ex
 )
-returning *
\ No newline at end of file
+select * from ex
@@ -0,0 +1,15 @@
+with taken as (
+    insert into agp_rate_limit (key, tat)
+    values (@key, now() + @emission_interval::interval)
+    on conflict (key) do update
+    set tat = greatest(agp_rate_limit.tat, now()) + @emission_interval::interval
+    where greatest(agp_rate_limit.tat, now()) + @emission_interval::interval - @burst_tolerance::interval <= now()
+    returning tat
+)
+select
+    (select count(*) from taken) > 0 as allowed,
+    coalesce((
+        select extract(epoch from greatest(tat, now()) + @emission_interval::interval - @burst_tolerance::interval - now())
+        from agp_rate_limit
+        where key = @key
+    ), 0)::double precision as retry_after
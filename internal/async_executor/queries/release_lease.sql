@@ -0,0 +1,3 @@
+delete from agp_lease 
+where key = @key
+returning *
\ No newline at end of file
@@ -0,0 +1,18 @@
+with ex as (
+    update agp_execution
+    set 
+        status = 'PENDING',
+        picked_at = null,
+        picked_by = null,
+        dead_at = null,
+        progress = null
+    where id = @id
+    and picked_by = @picked_by
+    and attempt = @attempt
+    and status = 'RUNNING'
+    returning *
+), ev as (
+    insert into agp_execution_event (execution_id, type, actor, status, data)
+    select id, 'REQUEUED', @picked_by, status, jsonb_build_object('reason', 'worker drained') from ex
+)
+select * from ex
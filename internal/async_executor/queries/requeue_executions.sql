@@ -0,0 +1,16 @@
+with ex as (
+    update agp_execution
+    set 
+        status = 'PENDING',
+        picked_at = null,
+        picked_by = null,
+        dead_at = null,
+        progress = null
+    where status = 'RUNNING'
+    {{template "execution_filter.sql" .}}
+    returning id, status
+), ev as (
+    insert into agp_execution_event (execution_id, type, actor, status, data)
+    select id, 'REQUEUED', @actor, status, jsonb_build_object('reason', 'admin') from ex
+)
+select id from ex
@@ -0,0 +1 @@
+select version from {{.version_table}}
\ No newline at end of file
@@ -0,0 +1,10 @@
+select 
+    tier,
+    count(*) filter (where status = 'PENDING') as pending,
+    count(*) filter (where status = 'RUNNING') as running,
+    count(distinct picked_by) filter (where status = 'RUNNING') as workers,
+    min(created_at) filter (where status = 'PENDING') as oldest_pending_at
+from agp_execution
+where status in ('PENDING', 'RUNNING')
+group by tier
+order by tier
@@ -0,0 +1,7 @@
+update agp_execution
+set
+    expires_at = coalesce(now() + @ttl::interval, expires_at),
+    pinned = coalesce(@pinned::boolean, pinned)
+where id = @id
+and status in ('CANCELED', 'FAILED', 'SUCCEEDED')
+returning *
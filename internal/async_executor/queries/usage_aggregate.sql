@@ -0,0 +1,19 @@
+select
+    date_trunc(@granularity, created_at, 'UTC') as period,
+    quota_key,
+    tier,
+    source,
+    count(*) as queries,
+    sum(rows_read)::bigint as rows_read,
+    sum(bytes_read)::bigint as bytes_read,
+    sum(elapsed_ms)::bigint as elapsed_ms,
+    sum(result_rows)::bigint as result_rows,
+    sum(result_bytes)::bigint as result_bytes
+from agp_usage
+where created_at >= @from
+and created_at < @to
+{{if ne .quota_key "" }}
+and quota_key = @quota_key
+{{end}}
+group by 1, 2, 3, 4
+order by 1, 2, 3, 4
@@ -0,0 +1,22 @@
+insert into agp_usage (
+    quota_key,
+    tier,
+    source,
+    execution_id,
+    rows_read,
+    bytes_read,
+    elapsed_ms,
+    result_rows,
+    result_bytes
+) values (
+    @quota_key,
+    @tier,
+    @source,
+    @execution_id,
+    @rows_read,
+    @bytes_read,
+    @elapsed_ms,
+    @result_rows,
+    @result_bytes
+)
+returning *
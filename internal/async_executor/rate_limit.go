@@ -0,0 +1,183 @@
+package async_executor
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/agnosticeng/agp/internal/async_executor/queries"
+	"github.com/jackc/pgx/v5"
+)
+
+type TakeTokenOptions struct {
+	RequestsPerSecond float64
+	Burst             int
+}
+
+// TakeToken implements a GCRA token bucket per key; when no token is available,
+// it returns the duration after which the next one will be.
+func (aex *AsyncExecutor) TakeToken(ctx context.Context, key string, opts TakeTokenOptions) (bool, time.Duration, error) {
+	if opts.RequestsPerSecond <= 0 {
+		return true, 0, nil
+	}
+
+	if opts.Burst <= 0 {
+		opts.Burst = int(math.Max(1, math.Ceil(opts.RequestsPerSecond)))
+	}
+
+	var emissionInterval = time.Duration(float64(time.Second) / opts.RequestsPerSecond)
+
+	rows, err := queries.Query(ctx, aex.pool, "rate_limit_take.sql", pgx.NamedArgs{
+		"key":               key,
+		"emission_interval": emissionInterval,
+		"burst_tolerance":   emissionInterval * time.Duration(opts.Burst),
+	})
+
+	if err != nil {
+		return false, 0, err
+	}
+
+	res, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[struct {
+		Allowed    bool
+		RetryAfter float64
+	}])
+
+	if err != nil {
+		return false, 0, err
+	}
+
+	return res.Allowed, time.Duration(res.RetryAfter * float64(time.Second)), nil
+}
+
+type AcquireConcurrencySlotOptions struct {
+	Limit int
+	TTL   time.Duration
+}
+
+// AcquireConcurrencySlot reserves one of the opts.Limit slots of key, it returns nil
+// when all slots are taken. Slots not refreshed within opts.TTL are considered released.
+func (aex *AsyncExecutor) AcquireConcurrencySlot(
+	ctx context.Context,
+	key string,
+	owner string,
+	opts AcquireConcurrencySlotOptions,
+) (*ConcurrencySlot, error) {
+	if opts.TTL == 0 {
+		opts.TTL = time.Minute
+	}
+
+	var tx, err = aex.pool.Begin(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(context.Background())
+
+	_, err = queries.Exec(ctx, tx, "concurrency_slot_advisory_lock.sql", pgx.NamedArgs{
+		"key": fnv1aHashInt64Sum(key),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = queries.Exec(ctx, tx, "concurrency_slot_purge.sql", pgx.NamedArgs{
+		"key": key,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := queries.Query(ctx, tx, "concurrency_slot_acquire.sql", pgx.NamedArgs{
+		"key":   key,
+		"owner": owner,
+		"ttl":   opts.TTL,
+		"limit": opts.Limit,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	slot, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[ConcurrencySlot])
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &slot, tx.Commit(context.Background())
+}
+
+func (aex *AsyncExecutor) RefreshConcurrencySlot(ctx context.Context, id int64, ttl time.Duration) error {
+	_, err := queries.Exec(ctx, aex.pool, "concurrency_slot_refresh.sql", pgx.NamedArgs{
+		"id":  id,
+		"ttl": ttl,
+	})
+
+	return err
+}
+
+func (aex *AsyncExecutor) ReleaseConcurrencySlot(ctx context.Context, id int64) error {
+	_, err := queries.Exec(ctx, aex.pool, "concurrency_slot_release.sql", pgx.NamedArgs{
+		"id": id,
+	})
+
+	return err
+}
+
+// ErrTooManyInFlightExecutions is returned by Create when the creator already has as many PENDING or
+// RUNNING executions as the limit set by WithInFlightLimit.
+var ErrTooManyInFlightExecutions = errors.New("too many in-flight executions")
+
+type inFlightLimitKey struct{}
+
+// WithInFlightLimit makes the executions created with the returned context count against a limit of
+// PENDING or RUNNING executions per creator; the check and the creation happen in one transaction.
+func WithInFlightLimit(ctx context.Context, limit int) context.Context {
+	return context.WithValue(ctx, inFlightLimitKey{}, limit)
+}
+
+// checkInFlight serializes the creations of a creator for the rest of the transaction and fails with
+// ErrTooManyInFlightExecutions when the limit of the context is reached.
+func checkInFlight(ctx context.Context, tx pgx.Tx, createdBy string) error {
+	var limit, _ = ctx.Value(inFlightLimitKey{}).(int)
+
+	if limit <= 0 {
+		return nil
+	}
+
+	_, err := queries.Exec(ctx, tx, "in_flight_advisory_lock.sql", pgx.NamedArgs{
+		"key": fnv1aHashInt64Sum("in_flight:" + createdBy),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	rows, err := queries.Query(ctx, tx, "count_in_flight_by_created_by.sql", pgx.NamedArgs{
+		"created_by": createdBy,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	count, err := pgx.CollectExactlyOneRow(rows, pgx.RowTo[int64])
+
+	if err != nil {
+		return err
+	}
+
+	if count >= int64(limit) {
+		return ErrTooManyInFlightExecutions
+	}
+
+	return nil
+}
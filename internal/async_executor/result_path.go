@@ -0,0 +1,115 @@
+package async_executor
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const defaultResultStoragePathTemplate = "{{id}}"
+
+type ResultStorageTierConfig struct {
+	Tier   string
+	Prefix string
+}
+
+var pathTemplatePlaceholder = regexp.MustCompile(`\{\{\s*([a-z_]+)\s*\}\}`)
+
+// pathTemplateValues returns the values of the placeholders a result path template can use; values are
+// path-escaped so that a caller-controlled string cannot add path segments. The id of attempts after
+// the first one is suffixed with the attempt, so that a requeued execution never writes where the
+// run it replaces may still be writing.
+func pathTemplateValues(ex *Execution, format ResultFormat, compression ResultCompression) map[string]string {
+	var (
+		t  = ex.CreatedAt.UTC()
+		id = strconv.FormatInt(ex.Id, 10)
+	)
+
+	if ex.Attempt > 1 {
+		id += "-" + strconv.FormatInt(ex.Attempt, 10)
+	}
+
+	return map[string]string{
+		"id":         id,
+		"tier":       url.PathEscape(ex.Tier),
+		"created_by": url.PathEscape(ex.CreatedBy),
+		"query_id":   url.PathEscape(ex.QueryId),
+		"yyyy":       fmt.Sprintf("%04d", t.Year()),
+		"mm":         fmt.Sprintf("%02d", t.Month()),
+		"dd":         fmt.Sprintf("%02d", t.Day()),
+		"hh":         fmt.Sprintf("%02d", t.Hour()),
+		"ext":        strings.ToLower(string(format)) + compressionExtension(compression),
+	}
+}
+
+func validateResultStoragePathTemplate(tmpl string) error {
+	var (
+		values = pathTemplateValues(&Execution{}, ResultFormatJSON, ResultCompressionNone)
+		hasId  bool
+	)
+
+	for _, m := range pathTemplatePlaceholder.FindAllStringSubmatch(tmpl, -1) {
+		if _, found := values[m[1]]; !found {
+			return fmt.Errorf("unknown placeholder in result storage path template: %s", m[0])
+		}
+
+		hasId = hasId || m[1] == "id"
+	}
+
+	// results of distinct executions must never share a path
+	if !hasId {
+		return fmt.Errorf("result storage path template must contain the {{id}} placeholder")
+	}
+
+	return nil
+}
+
+func (aex *AsyncExecutor) resultStoragePrefix(tier string) string {
+	for _, t := range aex.conf.ResultStorageTiers {
+		if t.Tier == tier && len(t.Prefix) > 0 {
+			return t.Prefix
+		}
+	}
+
+	return aex.conf.ResultStoragePrefix
+}
+
+// newResultURL resolves where the result of an execution is written from the current configuration,
+// it returns the full URL and the path relative to the storage prefix.
+func (aex *AsyncExecutor) newResultURL(ex *Execution, format ResultFormat, compression ResultCompression) (*url.URL, string, error) {
+	var (
+		values = pathTemplateValues(ex, format, compression)
+		p      = pathTemplatePlaceholder.ReplaceAllStringFunc(aex.conf.ResultStoragePathTemplate, func(s string) string {
+			return values[pathTemplatePlaceholder.FindStringSubmatch(s)[1]]
+		})
+	)
+
+	u, err := url.Parse(aex.resultStoragePrefix(ex.Tier))
+
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to build result url: %w", err)
+	}
+
+	u.Path = path.Join(u.Path, p)
+	return u, p, nil
+}
+
+// ResultURL returns where the result of a completed execution is stored; it never depends on the current
+// configuration, except for results written before the full URL was recorded.
+func (aex *AsyncExecutor) ResultURL(ex *Execution) (*url.URL, error) {
+	if len(ex.Result.StorageUrl) > 0 {
+		return url.Parse(ex.Result.StorageUrl)
+	}
+
+	u, err := url.Parse(aex.conf.ResultStoragePrefix)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build result url: %w", err)
+	}
+
+	u.Path = path.Join(u.Path, ex.Result.StoragePath)
+	return u, nil
+}
@@ -0,0 +1,58 @@
+package async_executor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewResultURLAttempts(t *testing.T) {
+	var (
+		aex = AsyncExecutor{conf: AsyncExecutorConfig{
+			ResultStoragePrefix:       "s3://bucket/results",
+			ResultStoragePathTemplate: "{{tier}}/{{id}}.{{ext}}",
+		}}
+		ex = Execution{Id: 42, Tier: "default", CreatedAt: time.Date(2024, 3, 5, 14, 0, 0, 0, time.UTC)}
+	)
+
+	var cases = []struct {
+		attempt int64
+		path    string
+	}{
+		// executions picked before attempts were counted keep their path
+		{attempt: 0, path: "default/42.json.gz"},
+		{attempt: 1, path: "default/42.json.gz"},
+		{attempt: 2, path: "default/42-2.json.gz"},
+		{attempt: 10, path: "default/42-10.json.gz"},
+	}
+
+	for _, c := range cases {
+		ex.Attempt = c.attempt
+
+		u, p, err := aex.newResultURL(&ex, ResultFormatJSON, ResultCompressionGZIP)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if p != c.path || u.String() != "s3://bucket/results/"+c.path {
+			t.Errorf("attempt %d: got %s (%s), want %s", c.attempt, p, u, c.path)
+		}
+	}
+}
+
+func TestBackendQueryId(t *testing.T) {
+	var cases = []struct {
+		attempt int64
+		want    string
+	}{
+		{attempt: 0, want: "agp-execution-42"},
+		{attempt: 1, want: "agp-execution-42"},
+		{attempt: 3, want: "agp-execution-42-3"},
+	}
+
+	for _, c := range cases {
+		if got := BackendQueryId(42, c.attempt); got != c.want {
+			t.Errorf("attempt %d: got %s, want %s", c.attempt, got, c.want)
+		}
+	}
+}
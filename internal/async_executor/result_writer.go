@@ -0,0 +1,158 @@
+package async_executor
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/agnosticeng/agp/internal/backend"
+)
+
+// resultWriter stores the result of an execution as the backend reads its rows, computing its column
+// statistics and preview on the way, so that results are never held in memory.
+type resultWriter struct {
+	aex     *AsyncExecutor
+	ctx     context.Context
+	ex      *Execution
+	url     *url.URL
+	path    string
+	w       io.WriteCloser
+	cw      io.WriteCloser
+	stored  *hashingWriter
+	content *hashingWriter
+	enc     *backend.ResultEncoder
+	closed  bool
+	stats   *columnStatsCollector
+	preview *previewBuilder
+}
+
+func (aex *AsyncExecutor) newResultWriter(ctx context.Context, ex *Execution) *resultWriter {
+	var rw = resultWriter{
+		aex:     aex,
+		ctx:     ctx,
+		ex:      ex,
+		preview: newPreviewBuilder(aex.conf.ResultPreviewRows, aex.conf.ResultPreviewMaxBytes),
+	}
+
+	if !aex.conf.DisableResultColumnStats {
+		rw.stats = newColumnStatsCollector()
+	}
+
+	return &rw
+}
+
+// open creates the result object, it is deferred to the first row or the end of the query so that
+// queries failing early do not leave objects behind.
+func (rw *resultWriter) open() error {
+	if rw.w != nil {
+		return nil
+	}
+
+	resUrl, path, err := rw.aex.newResultURL(rw.ex, rw.aex.conf.ResultStorageFormat, rw.aex.conf.ResultStorageCompression)
+
+	if err != nil {
+		return err
+	}
+
+	w, err := rw.aex.os.Writer(rw.ctx, resUrl)
+
+	if err != nil {
+		return err
+	}
+
+	rw.url, rw.path, rw.w = resUrl, path, w
+	rw.stored = newHashingWriter(w)
+
+	cw, err := Compressor(rw.aex.conf.ResultStorageCompression, rw.aex.conf.ResultStorageCompressionLevel, rw.stored)
+
+	if err != nil {
+		return err
+	}
+
+	rw.cw = cw
+	rw.content = newHashingWriter(cw)
+	rw.enc = backend.NewResultEncoder(rw.content, backend.Format(rw.aex.conf.ResultStorageFormat))
+	return nil
+}
+
+// writeRow is the row handler of the query.
+func (rw *resultWriter) writeRow(meta backend.Schema, row backend.Row) error {
+	if err := rw.open(); err != nil {
+		return err
+	}
+
+	if err := rw.enc.WriteRow(meta, row); err != nil {
+		return err
+	}
+
+	if rw.stats != nil {
+		rw.stats.add(meta, row)
+	}
+
+	return rw.preview.add(meta, row)
+}
+
+// close completes the stored result and returns its metadata and preview.
+func (rw *resultWriter) close(res *backend.Result, duration time.Duration) (*ResultMetadata, json.RawMessage, bool, error) {
+	if err := rw.open(); err != nil {
+		return nil, nil, false, err
+	}
+
+	if err := rw.enc.Close(res); err != nil {
+		return nil, nil, false, err
+	}
+
+	if err := rw.cw.Close(); err != nil {
+		return nil, nil, false, err
+	}
+
+	if err := rw.w.Close(); err != nil {
+		return nil, nil, false, err
+	}
+
+	rw.closed = true
+
+	var md ResultMetadata
+
+	md.Duration = duration
+	md.NumRows = res.Rows
+	md.Schema = res.Meta
+	md.StoragePath = rw.path
+	md.StorageUrl = rw.url.String()
+	md.StorageFormat = rw.aex.conf.ResultStorageFormat
+	md.StorageCompression = rw.aex.conf.ResultStorageCompression
+	md.StorageSize = rw.stored.n
+	md.StorageSha256 = rw.stored.sum()
+	md.ContentSize = rw.content.n
+	md.ContentSha256 = rw.content.sum()
+
+	if rw.stats != nil {
+		md.ColumnStats = rw.stats.stats(res.Meta)
+	}
+
+	preview, complete := rw.preview.build(res.Rows)
+	return &md, preview, complete, nil
+}
+
+// abort deletes the result written by this attempt of the execution, whether partially written by a
+// failed query or fully uploaded when the execution was lost in the meantime; the path is unique to
+// the attempt, so the result of another attempt is never deleted.
+func (rw *resultWriter) abort() {
+	if rw.w == nil {
+		return
+	}
+
+	if !rw.closed {
+		if rw.cw != nil {
+			rw.cw.Close()
+		}
+
+		rw.w.Close()
+	}
+
+	if err := rw.aex.os.Delete(context.WithoutCancel(rw.ctx), rw.url); err != nil {
+		rw.aex.logger.Warn("failed to delete partial result", "execution_id", rw.ex.Id, "error", err.Error())
+	}
+}
@@ -0,0 +1,43 @@
+package async_executor
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/agnosticeng/agp/internal/async_executor/queries"
+	"github.com/jackc/pgx/v5"
+)
+
+type RetentionOptions struct {
+	// Ttl sets the expiration to now + Ttl when not nil
+	Ttl *time.Duration
+	// Pinned protects the execution from GC when true
+	Pinned *bool
+}
+
+// UpdateRetention changes the expiration of a completed execution; it returns nil if the execution
+// does not exist or is not completed yet.
+func (aex *AsyncExecutor) UpdateRetention(ctx context.Context, id int64, opts RetentionOptions) (*Execution, error) {
+	rows, err := queries.Query(ctx, aex.pool, "update_retention.sql", pgx.NamedArgs{
+		"id":     id,
+		"ttl":    opts.Ttl,
+		"pinned": opts.Pinned,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	ex, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[Execution])
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ex, nil
+}
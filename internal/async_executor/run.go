@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/agnosticeng/agp/internal/async_executor/queries"
@@ -12,6 +13,10 @@ import (
 	"github.com/jackc/pgx/v5"
 )
 
+// ErrWorkerDrained must be used as the cancellation cause of the context passed to Run when the
+// worker stops before the execution completes, the execution is then requeued instead of failed.
+var ErrWorkerDrained = errors.New("worker drained")
+
 type RunOptions struct {
 	Tier                 string
 	MaxHeartbeatInterval time.Duration
@@ -40,19 +45,61 @@ func (aex *AsyncExecutor) Run(
 	var (
 		t0               = time.Now()
 		queryCtx, cancel = context.WithCancel(ctx)
+		lastProgress     atomic.Pointer[backend.Progress]
+		requeued         bool
+		usage            = Usage{
+			QuotaKey:    ex.CreatedBy,
+			Tier:        ex.Tier,
+			Source:      UsageSourceAsync,
+			ExecutionId: &ex.Id,
+		}
 	)
 
+	if ex.Timeout != nil {
+		cancel()
+		queryCtx, cancel = context.WithTimeout(ctx, *ex.Timeout)
+	}
+
 	defer cancel()
 
+	defer func() {
+		// a requeued execution runs again from scratch on another worker, which records its usage
+		if requeued {
+			return
+		}
+
+		if p := lastProgress.Load(); p != nil {
+			usage.RowsRead = int64(p.Rows)
+			usage.BytesRead = int64(p.Bytes)
+		}
+
+		usage.Elapsed = time.Since(t0)
+
+		if err := aex.RecordUsage(context.Background(), usage); err != nil {
+			aex.logger.Error(err.Error(), "execution_id", ex.Id)
+		}
+	}()
+
+	var fail = func(err error) error {
+		requeued = errors.Is(context.Cause(ctx), ErrWorkerDrained)
+		return aex.failExecution(ctx, ex, identity, err)
+	}
+
+	var rw = aex.newResultWriter(ctx, ex)
+
 	bkdRes, err := bkd.ExecuteQuery(
 		queryCtx,
 		ex.Query,
+		backend.WithQueryId(BackendQueryId(ex.Id, ex.Attempt)),
 		backend.WithParameters(ex.Secrets),
 		backend.WithQuotaKey(ex.CreatedBy),
+		backend.WithRowHandler(rw.writeRow),
 		backend.WithProgressHandler(func(p backend.Progress) {
-			ex, err := aex.heartbeatExecution(queryCtx, ex.Id, identity, opts.MaxHeartbeatInterval, p)
+			lastProgress.Store(&p)
 
-			if err != nil || ex.Status != StatusRunning {
+			hb, err := aex.heartbeatExecution(queryCtx, ex, identity, opts.MaxHeartbeatInterval, p)
+
+			if err != nil || hb.Status != StatusRunning {
 				if err != nil {
 					aex.logger.Error(err.Error())
 				}
@@ -62,67 +109,54 @@ func (aex *AsyncExecutor) Run(
 		}))
 
 	if err != nil {
-		return true, aex.completeExecution(ctx, ex.Id, identity, StatusFailed, nil, err.Error())
+		rw.abort()
+		return true, fail(err)
 	}
 
-	var duration = time.Since(t0)
-
-	js, err := aex.processResult(ctx, duration, bkdRes, ex)
+	md, preview, complete, err := rw.close(bkdRes, time.Since(t0))
 
 	if err != nil {
-		return true, aex.completeExecution(ctx, ex.Id, identity, StatusFailed, nil, err.Error())
+		rw.abort()
+		return true, fail(err)
 	}
 
-	return true, aex.completeExecution(ctx, ex.Id, identity, StatusSucceeded, js, "")
-}
-
-func (aex *AsyncExecutor) processResult(
-	ctx context.Context,
-	duration time.Duration,
-	bkdRes *backend.Result,
-	ex *Execution,
-) (json.RawMessage, error) {
-	resUrl, path, err := aex.buildResultURL(ex)
-
-	if err != nil {
-		return nil, err
-	}
+	usage.ResultRows = md.NumRows
+	usage.ResultBytes = md.StorageSize
 
-	w, err := aex.os.Writer(ctx, resUrl)
+	js, err := json.Marshal(md)
 
 	if err != nil {
-		return nil, err
+		return true, fail(err)
 	}
 
-	cw, err := Compressor(aex.conf.ResultStorageCompression, w)
+	err = aex.completeExecution(ctx, ex, identity, StatusSucceeded, js, preview, complete, "")
 
-	if err != nil {
-		return nil, err
+	// the execution was canceled or requeued during the upload, the result is not referenced by anyone
+	if errors.Is(err, errExecutionLost) {
+		rw.abort()
 	}
 
-	if err := json.NewEncoder(cw).Encode(bkdRes); err != nil {
-		return nil, err
-	}
+	return true, err
+}
 
-	if err := cw.Close(); err != nil {
-		return nil, err
-	}
+// progressEventInterval throttles PROGRESS execution events, heartbeats happen on every progress packet.
+const progressEventInterval = 10 * time.Second
 
-	if err := w.Close(); err != nil {
-		return nil, err
+// BackendQueryId is the query identifier an attempt of an execution runs with on the backend, it only
+// depends on the execution so that any process can abort the query; attempts after the first one
+// are suffixed so that a requeued execution does not collide with a run that is still being aborted.
+func BackendQueryId(executionId int64, attempt int64) string {
+	if attempt > 1 {
+		return fmt.Sprintf("agp-execution-%d-%d", executionId, attempt)
 	}
 
-	var md ResultMetadata
-
-	md.Duration = duration
-	md.NumRows = bkdRes.Rows
-	md.Schema = bkdRes.Meta
-	md.StoragePath = path
-	md.StorageCompression = aex.conf.ResultStorageCompression
-
-	return json.Marshal(md)
+	return fmt.Sprintf("agp-execution-%d", executionId)
 }
 
+// errExecutionLost is returned when a worker updates an execution it no longer runs, because it was
+// canceled, requeued or picked again since.
+var errExecutionLost = errors.New("execution is no longer run by this worker")
+
 func (aex *AsyncExecutor) pickExecution(
 	ctx context.Context,
 	tier string,
@@ -154,7 +188,7 @@ func (aex *AsyncExecutor) pickExecution(
 
 func (aex *AsyncExecutor) heartbeatExecution(
 	ctx context.Context,
-	id int64,
+	ex *Execution,
 	identity string,
 	maxHeartbeatInterval time.Duration,
 	progress backend.Progress,
@@ -162,58 +196,102 @@ func (aex *AsyncExecutor) heartbeatExecution(
 	js, err := json.Marshal(progress)
 
 	rows, err := queries.Query(ctx, aex.pool, "heartbeat.sql", pgx.StrictNamedArgs{
-		"id":                     id,
-		"picked_by":              identity,
-		"max_heartbeat_interval": maxHeartbeatInterval,
-		"progress":               json.RawMessage(js),
+		"id":                      ex.Id,
+		"picked_by":               identity,
+		"attempt":                 ex.Attempt,
+		"max_heartbeat_interval":  maxHeartbeatInterval,
+		"progress":                json.RawMessage(js),
+		"progress_event_interval": progressEventInterval,
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	ex, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[Execution])
+	hb, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[Execution])
 
 	if errors.Is(err, pgx.ErrNoRows) {
-		return nil, fmt.Errorf("tried to heartbeat execution %d, but is not owner", id)
+		return nil, fmt.Errorf("tried to heartbeat execution %d: %w", ex.Id, errExecutionLost)
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
-	return &ex, nil
+	return &hb, nil
+}
+
+// failExecution marks the execution as FAILED, unless the failure is caused by the worker being
+// drained, in which case the execution goes back to PENDING for another worker to pick it.
+func (aex *AsyncExecutor) failExecution(ctx context.Context, ex *Execution, identity string, err error) error {
+	if errors.Is(context.Cause(ctx), ErrWorkerDrained) {
+		return aex.requeueExecution(context.WithoutCancel(ctx), ex, identity)
+	}
+
+	return aex.completeExecution(ctx, ex, identity, StatusFailed, nil, nil, false, err.Error())
+}
+
+func (aex *AsyncExecutor) requeueExecution(ctx context.Context, ex *Execution, identity string) error {
+	rows, err := queries.Query(ctx, aex.pool, "requeue.sql", pgx.StrictNamedArgs{
+		"id":        ex.Id,
+		"picked_by": identity,
+		"attempt":   ex.Attempt,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	_, err = pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[Execution])
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("tried to requeue execution %d: %w", ex.Id, errExecutionLost)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	aex.logger.Info("execution requeued", "execution_id", ex.Id, "picked_by", identity)
+	return nil
 }
 
 func (aex *AsyncExecutor) completeExecution(
 	ctx context.Context,
-	id int64,
+	ex *Execution,
 	identity string,
 	status Status,
 	res json.RawMessage,
+	preview json.RawMessage,
+	previewComplete bool,
 	errorStr string,
 ) error {
 	rows, err := queries.Query(ctx, aex.pool, "complete.sql", pgx.StrictNamedArgs{
-		"id":        id,
-		"picked_by": identity,
-		"status":    status,
-		"result":    res,
-		"error":     errorStr,
+		"id":               ex.Id,
+		"picked_by":        identity,
+		"attempt":          ex.Attempt,
+		"status":           status,
+		"result":           res,
+		"preview":          preview,
+		"preview_complete": previewComplete,
+		"error":            errorStr,
 	})
 
 	if err != nil {
 		return err
 	}
 
-	_, err = pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[Execution])
+	completed, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[Execution])
 
 	if errors.Is(err, pgx.ErrNoRows) {
-		return fmt.Errorf("tried to complete execution %d, but is not owner", id)
+		// the execution may have been canceled or requeued while running, it must then stay as it is
+		return fmt.Errorf("tried to complete execution %d: %w", ex.Id, errExecutionLost)
 	}
 
 	if err != nil {
 		return err
 	}
 
+	aex.auditExecution(&completed)
 	return nil
 }
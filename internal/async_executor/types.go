@@ -9,8 +9,18 @@ import (
 type ResultCompression string
 
 const (
-	ResultCompressionNone ResultCompression = ""
-	ResultCompressionGZIP ResultCompression = "GZIP"
+	ResultCompressionNone   ResultCompression = ""
+	ResultCompressionGZIP   ResultCompression = "GZIP"
+	ResultCompressionZSTD   ResultCompression = "ZSTD"
+	ResultCompressionLZ4    ResultCompression = "LZ4"
+	ResultCompressionSnappy ResultCompression = "SNAPPY"
+)
+
+type ResultFormat string
+
+const (
+	ResultFormatJSON        ResultFormat = ResultFormat(backend.FormatJSON)
+	ResultFormatJSONCompact ResultFormat = ResultFormat(backend.FormatJSONCompact)
 )
 
 type Status string
@@ -21,6 +31,37 @@ const (
 	StatusCanceled  Status = "CANCELED"
 	StatusFailed    Status = "FAILED"
 	StatusSucceeded Status = "SUCCEEDED"
+	StatusExpired   Status = "EXPIRED"
+)
+
+type ExecutionEventType string
+
+const (
+	ExecutionEventCreated   ExecutionEventType = "CREATED"
+	ExecutionEventCollapsed ExecutionEventType = "COLLAPSED"
+	ExecutionEventPicked    ExecutionEventType = "PICKED"
+	ExecutionEventProgress  ExecutionEventType = "PROGRESS"
+	ExecutionEventRequeued  ExecutionEventType = "REQUEUED"
+	ExecutionEventCompleted ExecutionEventType = "COMPLETED"
+	ExecutionEventCanceled  ExecutionEventType = "CANCELED"
+	ExecutionEventDead      ExecutionEventType = "DEAD"
+	ExecutionEventExpired   ExecutionEventType = "EXPIRED"
+)
+
+type UsageSource string
+
+const (
+	UsageSourceAsync   UsageSource = "ASYNC"
+	UsageSourceSync    UsageSource = "SYNC"
+	UsageSourceChProxy UsageSource = "CHPROXY"
+)
+
+type UsageGranularity string
+
+const (
+	UsageGranularityHour  UsageGranularity = "HOUR"
+	UsageGranularityDay   UsageGranularity = "DAY"
+	UsageGranularityMonth UsageGranularity = "MONTH"
 )
 
 type SortBy string
@@ -35,7 +76,14 @@ type ResultMetadata struct {
 	NumRows            int64             `json:"num_rows"`
 	Duration           time.Duration     `json:"duration"`
 	StoragePath        string            `json:"storage_path"`
+	StorageUrl         string            `json:"storage_url"`
+	StorageFormat      ResultFormat      `json:"storage_format"`
 	StorageCompression ResultCompression `json:"storage_compression"`
+	StorageSize        int64             `json:"storage_size"`
+	StorageSha256      string            `json:"storage_sha256"`
+	ContentSize        int64             `json:"content_size"`
+	ContentSha256      string            `json:"content_sha256"`
+	ColumnStats        []ColumnStats     `json:"column_stats,omitempty"`
 }
 
 type Execution struct {
@@ -50,6 +98,7 @@ type Execution struct {
 	Secrets   map[string]string
 
 	CollapsedCounter int64
+	Attempt          int64
 	PickedAt         *time.Time
 	PickedBy         *string
 	Progress         *backend.Progress
@@ -57,6 +106,28 @@ type Execution struct {
 	CompletedAt      *time.Time
 	Result           *ResultMetadata
 	Error            *string
+	Ttl              *time.Duration
+	ExpiresAt        *time.Time
+	Pinned           bool
+	Timeout          *time.Duration
+}
+
+type TierStats struct {
+	Tier            string
+	Pending         int64
+	Running         int64
+	Workers         int64
+	OldestPendingAt *time.Time
+}
+
+type ExecutionEvent struct {
+	Id          int64
+	ExecutionId int64
+	CreatedAt   time.Time
+	Type        ExecutionEventType
+	Actor       *string
+	Status      *Status
+	Data        map[string]any
 }
 
 type Lease struct {
@@ -65,3 +136,35 @@ type Lease struct {
 	Owner     string
 	EndOfTerm time.Time
 }
+
+type ConcurrencySlot struct {
+	Id        int64
+	Key       string
+	Owner     string
+	ExpiresAt time.Time
+}
+
+type Usage struct {
+	QuotaKey    string
+	Tier        string
+	Source      UsageSource
+	ExecutionId *int64
+	RowsRead    int64
+	BytesRead   int64
+	Elapsed     time.Duration
+	ResultRows  int64
+	ResultBytes int64
+}
+
+type UsageAggregate struct {
+	Period      time.Time
+	QuotaKey    string
+	Tier        string
+	Source      UsageSource
+	Queries     int64
+	RowsRead    int64
+	BytesRead   int64
+	ElapsedMs   int64
+	ResultRows  int64
+	ResultBytes int64
+}
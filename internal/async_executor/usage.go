@@ -0,0 +1,104 @@
+package async_executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/agnosticeng/agp/internal/async_executor/queries"
+	"github.com/jackc/pgx/v5"
+	"github.com/samber/lo"
+)
+
+func (aex *AsyncExecutor) RecordUsage(ctx context.Context, usage Usage) error {
+	_, err := queries.Exec(ctx, aex.pool, "usage_insert.sql", pgx.NamedArgs{
+		"quota_key":    usage.QuotaKey,
+		"tier":         usage.Tier,
+		"source":       usage.Source,
+		"execution_id": usage.ExecutionId,
+		"rows_read":    usage.RowsRead,
+		"bytes_read":   usage.BytesRead,
+		"elapsed_ms":   usage.Elapsed.Milliseconds(),
+		"result_rows":  usage.ResultRows,
+		"result_bytes": usage.ResultBytes,
+	})
+
+	return err
+}
+
+type AggregateUsageOptions struct {
+	QuotaKey    string
+	Granularity UsageGranularity
+	From        time.Time
+	To          time.Time
+}
+
+func (aex *AsyncExecutor) AggregateUsage(ctx context.Context, opts AggregateUsageOptions) ([]*UsageAggregate, error) {
+	if len(opts.Granularity) == 0 {
+		opts.Granularity = UsageGranularityDay
+	}
+
+	if !lo.Contains([]UsageGranularity{UsageGranularityHour, UsageGranularityDay, UsageGranularityMonth}, opts.Granularity) {
+		return nil, fmt.Errorf("unknown usage granularity: %s", opts.Granularity)
+	}
+
+	if opts.To.IsZero() {
+		opts.To = time.Now()
+	}
+
+	if opts.From.IsZero() {
+		opts.From = opts.To.Add(-time.Hour * 24 * 30)
+	}
+
+	rows, err := queries.Query(ctx, aex.pool, "usage_aggregate.sql", pgx.NamedArgs{
+		"quota_key":   opts.QuotaKey,
+		"granularity": strings.ToLower(string(opts.Granularity)),
+		"from":        opts.From,
+		"to":          opts.To,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	aggs, err := pgx.CollectRows(rows, pgx.RowToStructByName[UsageAggregate])
+
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.ToSlicePtr(aggs), nil
+}
+
+// DailyUsage sums the usage of a quota key since the start of the current UTC day.
+func (aex *AsyncExecutor) DailyUsage(ctx context.Context, quotaKey string) (*UsageAggregate, error) {
+	var now = time.Now().UTC()
+
+	aggs, err := aex.AggregateUsage(ctx, AggregateUsageOptions{
+		QuotaKey:    quotaKey,
+		Granularity: UsageGranularityDay,
+		From:        now.Truncate(time.Hour * 24),
+		To:          now.Add(time.Minute),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	var res = UsageAggregate{
+		Period:   now.Truncate(time.Hour * 24),
+		QuotaKey: quotaKey,
+	}
+
+	for _, agg := range aggs {
+		res.Queries += agg.Queries
+		res.RowsRead += agg.RowsRead
+		res.BytesRead += agg.BytesRead
+		res.ElapsedMs += agg.ElapsedMs
+		res.ResultRows += agg.ResultRows
+		res.ResultBytes += agg.ResultBytes
+	}
+
+	return &res, nil
+}
@@ -1,11 +1,7 @@
 package async_executor
 
 import (
-	"fmt"
 	"hash/fnv"
-	"net/url"
-	"path/filepath"
-	"strconv"
 
 	"github.com/jackc/pgx/v5"
 )
@@ -26,17 +22,3 @@ func countRows(rows pgx.Rows) (int, error) {
 
 	return count, rows.Err()
 }
-
-func (aex *AsyncExecutor) buildResultURL(ex *Execution) (*url.URL, string, error) {
-	var (
-		u, err = url.Parse(aex.conf.ResultStoragePrefix)
-		path   = strconv.FormatInt(ex.Id, 10)
-	)
-
-	if err != nil {
-		return nil, "", fmt.Errorf("failed to build result url: %w", err)
-	}
-
-	u.Path = filepath.Join(u.Path, path)
-	return u, path, nil
-}
@@ -0,0 +1,61 @@
+package audit
+
+import (
+	"context"
+	"time"
+)
+
+type Source string
+
+const (
+	SourceAsync   Source = "ASYNC"
+	SourceSync    Source = "SYNC"
+	SourceChProxy Source = "CHPROXY"
+)
+
+type Outcome string
+
+const (
+	OutcomeSubmitted Outcome = "SUBMITTED"
+	OutcomeRejected  Outcome = "REJECTED"
+	OutcomeSucceeded Outcome = "SUCCEEDED"
+	OutcomeFailed    Outcome = "FAILED"
+	OutcomeCanceled  Outcome = "CANCELED"
+	// OutcomePromoted is recorded for sync queries handed over to an async execution
+	OutcomePromoted Outcome = "PROMOTED"
+)
+
+type Event struct {
+	Source      Source     `json:"source"`
+	Outcome     Outcome    `json:"outcome"`
+	Subject     string     `json:"subject,omitempty"`
+	QuotaKey    string     `json:"quota_key"`
+	ClientIp    string     `json:"client_ip,omitempty"`
+	Tier        string     `json:"tier"`
+	ExecutionId *int64     `json:"execution_id,omitempty"`
+	QueryHash   string     `json:"query_hash,omitempty"`
+	Query       string     `json:"query,omitempty"`
+	StatusCode  int        `json:"status_code,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	StartedAt   time.Time  `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+
+	// QueryTruncated is set when Query was cut at the capture limit, QueryHash then being the hash
+	// of the captured text
+	QueryTruncated bool `json:"query_truncated,omitempty"`
+}
+
+// Sink persists audit events; implementations must be safe for concurrent use.
+type Sink interface {
+	Write(ctx context.Context, ev Event) error
+	Close() error
+}
+
+type AuditConfig struct {
+	Dsn string
+}
+
+type NopSink struct{}
+
+func (NopSink) Write(context.Context, Event) error { return nil }
+func (NopSink) Close() error                       { return nil }
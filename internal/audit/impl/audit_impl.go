@@ -0,0 +1,32 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/agnosticeng/agp/internal/audit"
+	"github.com/agnosticeng/agp/internal/audit/impl/file"
+	"github.com/agnosticeng/agp/internal/audit/impl/postgres"
+)
+
+func NewSink(ctx context.Context, dsn string) (audit.Sink, error) {
+	if len(dsn) == 0 {
+		return audit.NopSink{}, nil
+	}
+
+	u, err := url.Parse(dsn)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "postgres", "postgresql":
+		return postgres.NewPostgresSink(ctx, dsn)
+	case "file":
+		return file.NewFileSink(ctx, u.Path)
+	default:
+		return nil, fmt.Errorf("unknwon audit sink scheme: %s", u.Scheme)
+	}
+}
@@ -0,0 +1,39 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/agnosticeng/agp/internal/audit"
+)
+
+// FileSink appends audit events to a file as JSON lines.
+type FileSink struct {
+	mu  sync.Mutex
+	f   *os.File
+	enc *json.Encoder
+}
+
+func NewFileSink(ctx context.Context, path string) (*FileSink, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o640)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileSink{f: f, enc: json.NewEncoder(f)}, nil
+}
+
+func (s *FileSink) Write(ctx context.Context, ev audit.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enc.Encode(ev)
+}
+
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Close()
+}
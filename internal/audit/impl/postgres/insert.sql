@@ -0,0 +1,31 @@
+insert into agp_audit_log (
+    source,
+    outcome,
+    subject,
+    quota_key,
+    client_ip,
+    tier,
+    execution_id,
+    query_hash,
+    query,
+    query_truncated,
+    status_code,
+    error,
+    started_at,
+    completed_at
+) values (
+    @source,
+    @outcome,
+    @subject,
+    @quota_key,
+    @client_ip,
+    @tier,
+    @execution_id,
+    @query_hash,
+    @query,
+    @query_truncated,
+    @status_code,
+    @error,
+    @started_at,
+    @completed_at
+)
@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/agnosticeng/agp/internal/audit"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed insert.sql
+var insertSQL string
+
+// PostgresSink writes audit events to the agp_audit_log table, which is never touched by execution GC.
+type PostgresSink struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresSink(ctx context.Context, dsn string) (*PostgresSink, error) {
+	pool, err := pgxpool.New(ctx, dsn)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &PostgresSink{pool: pool}, nil
+}
+
+func (s *PostgresSink) Write(ctx context.Context, ev audit.Event) error {
+	_, err := s.pool.Exec(ctx, insertSQL, pgx.NamedArgs{
+		"source":          ev.Source,
+		"outcome":         ev.Outcome,
+		"subject":         ev.Subject,
+		"quota_key":       ev.QuotaKey,
+		"client_ip":       ev.ClientIp,
+		"tier":            ev.Tier,
+		"execution_id":    ev.ExecutionId,
+		"query_hash":      ev.QueryHash,
+		"query":           ev.Query,
+		"query_truncated": ev.QueryTruncated,
+		"status_code":     ev.StatusCode,
+		"error":           ev.Error,
+		"started_at":      ev.StartedAt,
+		"completed_at":    ev.CompletedAt,
+	})
+
+	return err
+}
+
+func (s *PostgresSink) Close() error {
+	s.pool.Close()
+	return nil
+}
@@ -0,0 +1,172 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	v1 "github.com/agnosticeng/agp/internal/api/v1"
+	"github.com/agnosticeng/agp/internal/query_hasher"
+	"github.com/agnosticeng/agp/pkg/client_ip_middleware"
+)
+
+// maxQuerySize bounds the number of body bytes captured as query text, the request body
+// itself is forwarded untouched.
+const maxQuerySize = 1 << 16
+
+// Middleware records one event per request of an API whose request body is the SQL query.
+// It must be mounted behind the validation middleware so that claims are available. The outcome
+// is derived from the response status code unless the handler reports it with ReportQuery or
+// ReportPromotion.
+func Middleware(
+	logger *slog.Logger,
+	sink Sink,
+	source Source,
+	queryHasher query_hasher.QueryHasher,
+) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var (
+				claims = v1.ClaimsFromContext(r.Context())
+				ev     = Event{
+					Source:    source,
+					Subject:   claims.Subject,
+					QuotaKey:  claims.QuotaKey,
+					ClientIp:  client_ip_middleware.FromContext(r.Context()),
+					Tier:      claims.Tier,
+					StartedAt: time.Now(),
+				}
+				sw  = &statusWriter{ResponseWriter: w, statusCode: http.StatusOK}
+				rep report
+			)
+
+			ev.Query, ev.QueryTruncated = captureQuery(r)
+			ev.QueryHash = queryHasher(ev.Query)
+
+			next.ServeHTTP(sw, r.WithContext(withReport(r.Context(), &rep)))
+
+			var completedAt = time.Now()
+
+			ev.CompletedAt = &completedAt
+			ev.StatusCode = sw.statusCode
+			ev.Outcome = OutcomeFromStatusCode(sw.statusCode)
+			rep.apply(&ev)
+
+			if err := sink.Write(context.Background(), ev); err != nil {
+				logger.Error("failed to write audit event", "error", err.Error())
+			}
+		})
+	}
+}
+
+func OutcomeFromStatusCode(statusCode int) Outcome {
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		return OutcomeRejected
+	case statusCode >= 400:
+		return OutcomeFailed
+	default:
+		return OutcomeSucceeded
+	}
+}
+
+// captureQuery returns the query text of a request, truncated is set when the body exceeds maxQuerySize.
+func captureQuery(r *http.Request) (query string, truncated bool) {
+	query = r.URL.Query().Get("query")
+
+	if r.Body == nil {
+		return query, false
+	}
+
+	var buf bytes.Buffer
+
+	// one more byte tells whether the body goes beyond the limit
+	io.CopyN(&buf, r.Body, maxQuerySize+1)
+	r.Body = &multiReadCloser{Reader: io.MultiReader(bytes.NewReader(buf.Bytes()), r.Body), Closer: r.Body}
+
+	if truncated = buf.Len() > maxQuerySize; truncated {
+		buf.Truncate(maxQuerySize)
+	}
+
+	var body = buf.String()
+
+	switch contentType := r.Header.Get("Content-Type"); {
+	case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+		if values, err := url.ParseQuery(body); err == nil && values.Has("query") {
+			body = values.Get("query")
+		}
+	case strings.HasPrefix(contentType, "application/json"):
+		// the other fields (parameters, secrets, ...) are not recorded, nor is their truncation
+		if body = jsonQuery(buf.Bytes()); len(body) > 0 {
+			truncated = false
+		}
+	}
+
+	if len(query) > 0 && len(body) > 0 {
+		return query + "\n" + body, truncated
+	}
+
+	return query + body, truncated
+}
+
+// jsonQuery returns the sql field of a JSON body, which may be truncated after it.
+func jsonQuery(body []byte) string {
+	var dec = json.NewDecoder(bytes.NewReader(body))
+
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return ""
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+
+		if err != nil {
+			return ""
+		}
+
+		var value json.RawMessage
+
+		if err := dec.Decode(&value); err != nil {
+			return ""
+		}
+
+		if tok == "sql" {
+			var sql string
+			json.Unmarshal(value, &sql)
+			return sql
+		}
+	}
+
+	return ""
+}
+
+type multiReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (w *statusWriter) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
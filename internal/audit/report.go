@@ -0,0 +1,71 @@
+package audit
+
+import (
+	"context"
+	"sync"
+)
+
+type reportKey struct{}
+
+// report is the outcome of a query as told by its handler, it overrides the one derived from the
+// response status code.
+type report struct {
+	mu          sync.Mutex
+	set         bool
+	outcome     Outcome
+	executionId *int64
+	err         string
+}
+
+func withReport(ctx context.Context, rep *report) context.Context {
+	return context.WithValue(ctx, reportKey{}, rep)
+}
+
+func (rep *report) store(outcome Outcome, executionId *int64, err error) {
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+
+	rep.set, rep.outcome, rep.executionId = true, outcome, executionId
+
+	if err != nil {
+		rep.err = err.Error()
+	}
+}
+
+func (rep *report) apply(ev *Event) {
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+
+	if !rep.set {
+		return
+	}
+
+	ev.Outcome, ev.ExecutionId, ev.Error = rep.outcome, rep.executionId, rep.err
+}
+
+// ReportQuery records how the query of a request audited by Middleware ended, for responses whose
+// status is sent before the query completes, e.g. streamed results. It must be called before the
+// response is fully written.
+func ReportQuery(ctx context.Context, err error) {
+	var outcome = OutcomeSucceeded
+
+	switch {
+	case err == nil:
+	case ctx.Err() != nil:
+		outcome = OutcomeCanceled
+	default:
+		outcome = OutcomeFailed
+	}
+
+	if rep, ok := ctx.Value(reportKey{}).(*report); ok {
+		rep.store(outcome, nil, err)
+	}
+}
+
+// ReportPromotion records that the query of a request audited by Middleware was handed over to an
+// async execution.
+func ReportPromotion(ctx context.Context, executionId int64) {
+	if rep, ok := ctx.Value(reportKey{}).(*report); ok {
+		rep.store(OutcomePromoted, &executionId, nil)
+	}
+}
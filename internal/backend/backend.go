@@ -6,11 +6,18 @@ import (
 )
 
 type RunOptions struct {
+	QueryId         string
 	QuotaKey        string
 	ProgressHandler func(Progress)
+	RowHandler      RowHandler
 	Parameters      map[string]string
+	Settings        map[string]string
 }
 
+// RowHandler receives the rows of a result as they are scanned, the query is aborted when it
+// returns an error.
+type RowHandler func(meta Schema, row Row) error
+
 type RunOption func(*RunOptions)
 
 func BuildRunOptions(fns ...RunOption) *RunOptions {
@@ -36,18 +43,41 @@ func WithProgressHandler(h func(Progress)) RunOption {
 	}
 }
 
+// WithQueryId sets the identifier of the query on the backend, backends that support it use it
+// to abort the query server-side when the context is canceled.
+func WithQueryId(id string) RunOption {
+	return func(ro *RunOptions) {
+		ro.QueryId = id
+	}
+}
+
 func WithQuotaKey(key string) RunOption {
 	return func(ro *RunOptions) {
 		ro.QuotaKey = key
 	}
 }
 
+// WithRowHandler streams the rows of the result to the handler, they are then not kept in
+// Result.Data; Result.Rows still counts them.
+func WithRowHandler(h RowHandler) RunOption {
+	return func(ro *RunOptions) {
+		ro.RowHandler = h
+	}
+}
+
 func WithParameters(params map[string]string) RunOption {
 	return func(ro *RunOptions) {
 		ro.Parameters = params
 	}
 }
 
+// WithSettings sets per-query settings, backends without settings reject queries that have some.
+func WithSettings(settings map[string]string) RunOption {
+	return func(ro *RunOptions) {
+		ro.Settings = settings
+	}
+}
+
 type Column struct {
 	Name string `json:"name"`
 	Type string `json:"type"`
@@ -55,14 +85,21 @@ type Column struct {
 
 type Schema []Column
 
+// Row holds the values of a result row, in the order of the result schema.
+type Row []any
+
+// Result rows are kept as values rather than objects so that the column order is preserved and
+// duplicated column names (e.g. SELECT a.id, b.id) do not overwrite each other; use Encode to
+// marshal it.
 type Result struct {
-	Meta Schema           `json:"meta"`
-	Rows int64            `json:"rows"`
-	Data []map[string]any `json:"data"`
+	Meta Schema `json:"meta"`
+	Rows int64  `json:"rows"`
+	Data []Row  `json:"data"`
 }
 
 type Backend interface {
 	ExecuteQuery(ctx context.Context, query string, opts ...RunOption) (*Result, error)
+	Ping(ctx context.Context) error
 	Close() error
 }
 
@@ -0,0 +1,357 @@
+package backend
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+type Format string
+
+const (
+	// FormatJSON renders rows as objects keyed by column name, like the ClickHouse JSON format
+	FormatJSON Format = "JSON"
+	// FormatJSONCompact renders rows as arrays of values, like the ClickHouse JSONCompact format
+	FormatJSONCompact Format = "JSON_COMPACT"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case "":
+		return FormatJSON, nil
+	case FormatJSON, FormatJSONCompact:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown result format: %s", s)
+	}
+}
+
+// object is a row rendered as a JSON object with the keys in the order of the schema; duplicated
+// column names are repeated, as ClickHouse does.
+type object struct {
+	meta Schema
+	row  Row
+}
+
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+
+	for i, column := range o.meta {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(column.Name)
+
+		if err != nil {
+			return nil, err
+		}
+
+		var value any
+
+		if i < len(o.row) {
+			value = o.row[i]
+		}
+
+		js, err := json.Marshal(value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(js)
+	}
+
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// FormatRow returns a value that marshals a row of the given schema in the given format.
+func FormatRow(meta Schema, row Row, format Format) any {
+	if format == FormatJSONCompact {
+		return row
+	}
+
+	return object{meta: meta, row: row}
+}
+
+// FormatRows returns values that marshal the rows in the given format.
+func (res *Result) FormatRows(format Format) []any {
+	var rows = make([]any, len(res.Data))
+
+	for i, row := range res.Data {
+		rows[i] = FormatRow(res.Meta, row, format)
+	}
+
+	return rows
+}
+
+// Encode writes the result as a JSON document with meta, data and rows keys, in this order so that
+// it can be written and transcoded as a stream.
+func (res *Result) Encode(w io.Writer, format Format) error {
+	var enc = NewResultEncoder(w, format)
+
+	for _, row := range res.Data {
+		if err := enc.WriteRow(res.Meta, row); err != nil {
+			return err
+		}
+	}
+
+	return enc.Close(res)
+}
+
+// ResultEncoder writes a result in the layout of Encode one row at a time, so that results do not
+// have to be held in memory.
+type ResultEncoder struct {
+	w       io.Writer
+	format  Format
+	started bool
+	rows    int64
+}
+
+func NewResultEncoder(w io.Writer, format Format) *ResultEncoder {
+	return &ResultEncoder{w: w, format: format}
+}
+
+func (enc *ResultEncoder) start(meta Schema) error {
+	if enc.started {
+		return nil
+	}
+
+	enc.started = true
+
+	js, err := json.Marshal(meta)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(enc.w, `{"meta":%s,"data":[`, js)
+	return err
+}
+
+func (enc *ResultEncoder) WriteRow(meta Schema, row Row) error {
+	if err := enc.start(meta); err != nil {
+		return err
+	}
+
+	js, err := json.Marshal(FormatRow(meta, row, enc.format))
+
+	if err != nil {
+		return err
+	}
+
+	if enc.rows > 0 {
+		if _, err := io.WriteString(enc.w, ","); err != nil {
+			return err
+		}
+	}
+
+	enc.rows++
+	_, err = enc.w.Write(js)
+	return err
+}
+
+// Close completes the document with the schema of the result, which is only known once the query
+// completed when it has no rows, and the number of rows written.
+func (enc *ResultEncoder) Close(res *Result) error {
+	if err := enc.start(res.Meta); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(enc.w, `],"rows":%d}`+"\n", enc.rows)
+	return err
+}
+
+// Transcode reads a result encoded by Encode and writes it in the given format, one row at a time.
+func Transcode(w io.Writer, r io.Reader, format Format) error {
+	var (
+		dec  = json.NewDecoder(r)
+		meta Schema
+	)
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, "{"); err != nil {
+		return err
+	}
+
+	for first := true; dec.More(); first = false {
+		tok, err := dec.Token()
+
+		if err != nil {
+			return err
+		}
+
+		var key, _ = tok.(string)
+
+		if !first {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+
+		if _, err := io.WriteString(w, strconv.Quote(key)+":"); err != nil {
+			return err
+		}
+
+		switch key {
+		case "data":
+			err = transcodeRows(w, dec, meta, format)
+		case "meta":
+			var raw json.RawMessage
+
+			if err = dec.Decode(&raw); err == nil {
+				if err = json.Unmarshal(raw, &meta); err == nil {
+					_, err = w.Write(raw)
+				}
+			}
+		default:
+			var raw json.RawMessage
+
+			if err = dec.Decode(&raw); err == nil {
+				_, err = w.Write(raw)
+			}
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := expectDelim(dec, '}'); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "}\n")
+	return err
+}
+
+func transcodeRows(w io.Writer, dec *json.Decoder, meta Schema, format Format) error {
+	if err := expectDelim(dec, '['); err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+
+	for i := 0; dec.More(); i++ {
+		var raw json.RawMessage
+
+		if err := dec.Decode(&raw); err != nil {
+			return err
+		}
+
+		values, err := rowValues(raw, meta)
+
+		if err != nil {
+			return err
+		}
+
+		var row = make(Row, len(values))
+
+		for j, v := range values {
+			row[j] = v
+		}
+
+		js, err := json.Marshal(FormatRow(meta, row, format))
+
+		if err != nil {
+			return err
+		}
+
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+
+		if _, err := w.Write(js); err != nil {
+			return err
+		}
+	}
+
+	if err := expectDelim(dec, ']'); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "]")
+	return err
+}
+
+// rowValues returns the raw values of a row encoded as an array, or as an object whose keys are
+// matched to the schema columns, in order, so that duplicated names are kept apart.
+func rowValues(raw json.RawMessage, meta Schema) ([]json.RawMessage, error) {
+	var dec = json.NewDecoder(bytes.NewReader(raw))
+
+	tok, err := dec.Token()
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok {
+	case json.Delim('['):
+		var values []json.RawMessage
+		return values, json.Unmarshal(raw, &values)
+	case json.Delim('{'):
+		var (
+			values   = make([]json.RawMessage, len(meta))
+			assigned = make([]bool, len(meta))
+		)
+
+		for dec.More() {
+			tok, err := dec.Token()
+
+			if err != nil {
+				return nil, err
+			}
+
+			var v json.RawMessage
+
+			if err := dec.Decode(&v); err != nil {
+				return nil, err
+			}
+
+			for i, column := range meta {
+				if !assigned[i] && column.Name == tok {
+					values[i], assigned[i] = v, true
+					break
+				}
+			}
+		}
+
+		for i := range values {
+			if !assigned[i] {
+				values[i] = json.RawMessage("null")
+			}
+		}
+
+		return values, nil
+	default:
+		return nil, fmt.Errorf("invalid result row: %s", raw)
+	}
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+
+	if err != nil {
+		return err
+	}
+
+	if tok != delim {
+		return fmt.Errorf("invalid result: expected %s, got %v", delim, tok)
+	}
+
+	return nil
+}
@@ -4,12 +4,23 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"strings"
 
 	"github.com/agnosticeng/agp/internal/backend"
 	"github.com/agnosticeng/agp/internal/backend/impl/clickhouse"
+	"github.com/agnosticeng/agp/internal/backend/impl/duckdb"
+	"github.com/agnosticeng/agp/internal/backend/impl/postgres"
+	"github.com/agnosticeng/agp/internal/backend/impl/sqldb"
 )
 
-func NewBackend(ctx context.Context, dsn string) (backend.Backend, error) {
+type BackendConfig struct {
+	Dsn        string
+	Clickhouse clickhouse.ClickhouseBackendConfig
+}
+
+func NewBackend(ctx context.Context, conf BackendConfig) (backend.Backend, error) {
+	var dsn = conf.Dsn
+
 	u, err := url.Parse(dsn)
 
 	if err != nil {
@@ -18,8 +29,17 @@ func NewBackend(ctx context.Context, dsn string) (backend.Backend, error) {
 
 	switch u.Scheme {
 	case "clickhouse":
-		return clickhouse.NewClickhouseBackend(ctx, dsn)
+		return clickhouse.NewClickhouseBackend(ctx, dsn, conf.Clickhouse)
+	case "duckdb":
+		return duckdb.NewDuckDBBackend(ctx, dsn)
+	case "postgres", "postgresql":
+		return postgres.NewPostgresBackend(ctx, dsn)
 	default:
+		// generic database/sql drivers are addressed as sql+<driver>:<driver dsn>
+		if driver, found := strings.CutPrefix(u.Scheme, "sql+"); found {
+			return sqldb.OpenSQLBackend(ctx, driver, strings.TrimPrefix(dsn, u.Scheme+":"))
+		}
+
 		return nil, fmt.Errorf("unknwon backend scheme: %s", u.Scheme)
 	}
 }
@@ -2,34 +2,96 @@ package clickhouse
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
 	"reflect"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 	"github.com/agnosticeng/agp/internal/backend"
+	"github.com/google/uuid"
+	slogctx "github.com/veqryn/slog-context"
 )
 
 type ClickhouseBackend struct {
-	conn driver.Conn
+	conf     ClickhouseBackendConfig
+	logger   *slog.Logger
+	replicas []*replica
+	encoder  *jsonEncoder
+	next     atomic.Uint64
+	cancel   context.CancelFunc
 }
 
-func NewClickhouseBackend(ctx context.Context, dsn string) (*ClickhouseBackend, error) {
+func NewClickhouseBackend(ctx context.Context, dsn string, conf ClickhouseBackendConfig) (*ClickhouseBackend, error) {
 	chopts, err := clickhouse.ParseDSN(dsn)
 
 	if err != nil {
 		return nil, err
 	}
 
-	chconn, err := clickhouse.Open(chopts)
+	if err := conf.apply(chopts); err != nil {
+		return nil, err
+	}
+
+	if len(conf.Strategy) == 0 {
+		conf.Strategy = ReplicaSelectionInOrder
+	}
+
+	if conf.HealthCheckInterval == 0 {
+		conf.HealthCheckInterval = 10 * time.Second
+	}
+
+	if conf.HealthCheckTimeout == 0 {
+		conf.HealthCheckTimeout = 5 * time.Second
+	}
+
+	if conf.KillQueryTimeout == 0 {
+		conf.KillQueryTimeout = 10 * time.Second
+	}
+
+	encoder, err := newJSONEncoder(conf.Json)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return &ClickhouseBackend{chconn}, nil
+	var b = ClickhouseBackend{
+		conf:    conf,
+		logger:  slogctx.FromCtx(ctx),
+		encoder: encoder,
+	}
+
+	for _, addr := range chopts.Addr {
+		var opts = *chopts
+		opts.Addr = []string{addr}
+
+		chconn, err := clickhouse.Open(&opts)
+
+		if err != nil {
+			b.Close()
+			return nil, err
+		}
+
+		var r = replica{addr: addr, conn: chconn}
+		r.healthy.Store(true)
+		b.replicas = append(b.replicas, &r)
+	}
+
+	// health checks are disabled for single replica backends, there is nothing to fail over to
+	if len(b.replicas) > 1 && conf.HealthCheckInterval > 0 {
+		var loopCtx, cancel = context.WithCancel(context.Background())
+		b.cancel = cancel
+		go b.healthCheckLoop(slogctx.NewCtx(loopCtx, b.logger))
+	}
+
+	return &b, nil
 }
 
-func (b *ClickhouseBackend) ExecuteQuery(ctx context.Context, query string, optfns ...backend.RunOption) (*backend.Result, error) {
+func (b *ClickhouseBackend) ExecuteQuery(ctx context.Context, query string, optfns ...backend.RunOption) (_ *backend.Result, err error) {
 	var (
 		runOpts = backend.BuildRunOptions(optfns...)
 	)
@@ -47,6 +109,12 @@ func (b *ClickhouseBackend) ExecuteQuery(ctx context.Context, query string, optf
 		}))
 	}
 
+	if len(runOpts.QueryId) == 0 {
+		runOpts.QueryId = "agp-" + uuid.Must(uuid.NewV7()).String()
+	}
+
+	ctx = clickhouse.Context(ctx, clickhouse.WithQueryID(runOpts.QueryId))
+
 	if len(runOpts.QuotaKey) > 0 {
 		ctx = clickhouse.Context(ctx, clickhouse.WithQuotaKey(runOpts.QuotaKey))
 	}
@@ -55,18 +123,51 @@ func (b *ClickhouseBackend) ExecuteQuery(ctx context.Context, query string, optf
 		ctx = clickhouse.Context(ctx, clickhouse.WithParameters(runOpts.Parameters))
 	}
 
-	queryRes, err := b.conn.Query(ctx, query)
+	if len(runOpts.Settings) > 0 {
+		var settings = make(clickhouse.Settings, len(runOpts.Settings))
+
+		for k, v := range runOpts.Settings {
+			settings[k] = v
+		}
+
+		ctx = clickhouse.Context(ctx, clickhouse.WithSettings(settings))
+	}
+
+	queryRes, r, err := b.query(ctx, query, runOpts.QueryId)
 
 	if err != nil {
 		return nil, err
 	}
 
+	var drained bool
+
+	defer r.inFlight.Add(-1)
+	defer queryRes.Close()
+
+	// the query keeps running on the server when rows are left unread, e.g. on cancellation or when
+	// the row handler fails, closing the rows does not stop it
+	defer func() {
+		if ctx.Err() != nil || (err != nil && !drained) {
+			b.killQuery(r, runOpts.QueryId)
+		}
+	}()
+
 	var (
 		columnTypes = queryRes.ColumnTypes()
 		columnNames = queryRes.Columns()
-		rows        = make([]map[string]any, 0)
+		chTypes     = make([]*chType, len(columnTypes))
+		res         = backend.Result{Data: make([]backend.Row, 0)}
 	)
 
+	for i, ct := range columnTypes {
+		chTypes[i] = parseType(ct.DatabaseTypeName())
+
+		res.Meta = append(res.Meta, backend.Column{
+			Name: columnNames[i],
+			Type: ct.DatabaseTypeName(),
+		})
+	}
+
 	for queryRes.Next() {
 		var values = make([]any, len(columnNames))
 
@@ -78,30 +179,121 @@ func (b *ClickhouseBackend) ExecuteQuery(ctx context.Context, query string, optf
 			return nil, err
 		}
 
-		var row = make(map[string]any)
+		var row = make(backend.Row, len(values))
 
 		for i, v := range values {
-			row[columnNames[i]] = v
+			row[i] = b.encoder.encode(chTypes[i], v)
 		}
 
-		rows = append(rows, row)
+		res.Rows++
+
+		if runOpts.RowHandler == nil {
+			res.Data = append(res.Data, row)
+		} else if err := runOpts.RowHandler(res.Meta, row); err != nil {
+			return nil, err
+		}
 	}
 
-	var res = backend.Result{
-		Rows: int64(len(rows)),
-		Data: rows,
+	drained = true
+	return &res, queryRes.Err()
+}
+
+// query sends the query to the first replica that accepts it; the caller must decrement
+// the in-flight counter of the returned replica once done with the rows.
+func (b *ClickhouseBackend) query(ctx context.Context, query string, queryId string) (driver.Rows, *replica, error) {
+	var errs []error
+
+	for _, r := range b.candidates() {
+		r.inFlight.Add(1)
+
+		rows, err := r.conn.Query(ctx, query)
+
+		if err == nil {
+			return rows, r, nil
+		}
+
+		r.inFlight.Add(-1)
+
+		// the query may have reached the server before the context was canceled
+		if ctx.Err() != nil {
+			b.killQuery(r, queryId)
+			return nil, nil, err
+		}
+
+		// server-side exceptions would fail the same way on any replica
+		if exception := (*clickhouse.Exception)(nil); errors.As(err, &exception) {
+			return nil, nil, err
+		}
+
+		b.logger.Warn("clickhouse replica failed, trying next one", "addr", r.addr, "error", err.Error())
+		r.healthy.Store(false)
+		errs = append(errs, fmt.Errorf("%s: %w", r.addr, err))
 	}
 
-	for i := 0; i < len(columnNames); i++ {
-		res.Meta = append(res.Meta, backend.Column{
-			Name: columnNames[i],
-			Type: columnTypes[i].DatabaseTypeName(),
-		})
+	return nil, nil, errors.Join(errs...)
+}
+
+// killQuery aborts the query on the server, closing the client connection is not enough for
+// ClickHouse to stop executing it.
+func (b *ClickhouseBackend) killQuery(r *replica, queryId string) {
+	var ctx, cancel = context.WithTimeout(context.Background(), b.conf.KillQueryTimeout)
+	defer cancel()
+
+	var logger = b.logger.With("addr", r.addr, "query_id", queryId)
+
+	if err := r.conn.Exec(ctx, fmt.Sprintf("KILL QUERY WHERE query_id = '%s' SYNC", escapeString(queryId))); err != nil {
+		logger.Error("failed to kill query", "error", err.Error())
+		return
 	}
 
-	return &res, queryRes.Err()
+	var running uint64
+
+	if err := r.conn.QueryRow(
+		ctx,
+		fmt.Sprintf("SELECT count() FROM system.processes WHERE query_id = '%s'", escapeString(queryId)),
+	).Scan(&running); err != nil {
+		logger.Error("failed to confirm query was killed", "error", err.Error())
+		return
+	}
+
+	if running > 0 {
+		logger.Error("query is still running after being killed")
+		return
+	}
+
+	logger.Info("query killed")
+}
+
+func escapeString(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s)
+}
+
+// Ping succeeds as long as one replica is reachable, since queries fail over to it.
+func (b *ClickhouseBackend) Ping(ctx context.Context) error {
+	var errs []error
+
+	for _, r := range b.replicas {
+		if err := r.conn.Ping(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.addr, err))
+			continue
+		}
+
+		return nil
+	}
+
+	return errors.Join(errs...)
 }
 
 func (b *ClickhouseBackend) Close() error {
-	return b.conn.Close()
+	if b.cancel != nil {
+		b.cancel()
+	}
+
+	var errs []error
+
+	for _, r := range b.replicas {
+		errs = append(errs, r.conn.Close())
+	}
+
+	return errors.Join(errs...)
 }
@@ -0,0 +1,137 @@
+package clickhouse
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+type ReplicaSelectionStrategy string
+
+const (
+	ReplicaSelectionInOrder     ReplicaSelectionStrategy = "IN_ORDER"
+	ReplicaSelectionRoundRobin  ReplicaSelectionStrategy = "ROUND_ROBIN"
+	ReplicaSelectionLeastLoaded ReplicaSelectionStrategy = "LEAST_LOADED"
+)
+
+type TLSConfig struct {
+	CaFile             string
+	CertFile           string
+	KeyFile            string
+	ServerName         string
+	InsecureSkipVerify bool
+}
+
+// ClickhouseBackendConfig complements the DSN, every replica listed in the DSN host
+// (e.g. clickhouse://host1:9000,host2:9000/default) gets its own connection pool.
+type ClickhouseBackendConfig struct {
+	Strategy            ReplicaSelectionStrategy
+	HealthCheckInterval time.Duration
+	HealthCheckTimeout  time.Duration
+	KillQueryTimeout    time.Duration
+	MaxOpenConns        int
+	MaxIdleConns        int
+	ConnMaxLifetime     time.Duration
+	DialTimeout         time.Duration
+	Compression         string
+	CompressionLevel    int
+	Tls                 *TLSConfig
+	Json                JSONEncoderConfig
+}
+
+func (conf ClickhouseBackendConfig) apply(opts *clickhouse.Options) error {
+	if conf.MaxOpenConns > 0 {
+		opts.MaxOpenConns = conf.MaxOpenConns
+	}
+
+	if conf.MaxIdleConns > 0 {
+		opts.MaxIdleConns = conf.MaxIdleConns
+	}
+
+	if conf.ConnMaxLifetime > 0 {
+		opts.ConnMaxLifetime = conf.ConnMaxLifetime
+	}
+
+	if conf.DialTimeout > 0 {
+		opts.DialTimeout = conf.DialTimeout
+	}
+
+	if len(conf.Compression) > 0 {
+		method, err := compressionMethod(conf.Compression)
+
+		if err != nil {
+			return err
+		}
+
+		opts.Compression = &clickhouse.Compression{Method: method, Level: conf.CompressionLevel}
+	}
+
+	if conf.Tls != nil {
+		tlsConf, err := conf.Tls.build()
+
+		if err != nil {
+			return err
+		}
+
+		opts.TLS = tlsConf
+	}
+
+	return nil
+}
+
+func compressionMethod(s string) (clickhouse.CompressionMethod, error) {
+	for _, method := range []clickhouse.CompressionMethod{
+		clickhouse.CompressionNone,
+		clickhouse.CompressionLZ4,
+		clickhouse.CompressionZSTD,
+		clickhouse.CompressionGZIP,
+		clickhouse.CompressionDeflate,
+		clickhouse.CompressionBrotli,
+	} {
+		if strings.EqualFold(method.String(), s) {
+			return method, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown compression method: %s", s)
+}
+
+func (conf TLSConfig) build() (*tls.Config, error) {
+	var tlsConf = tls.Config{
+		ServerName:         conf.ServerName,
+		InsecureSkipVerify: conf.InsecureSkipVerify,
+	}
+
+	if len(conf.CaFile) > 0 {
+		pem, err := os.ReadFile(conf.CaFile)
+
+		if err != nil {
+			return nil, err
+		}
+
+		var pool = x509.NewCertPool()
+
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in CA file: %s", conf.CaFile)
+		}
+
+		tlsConf.RootCAs = pool
+	}
+
+	if len(conf.CertFile) > 0 || len(conf.KeyFile) > 0 {
+		keypair, err := tls.LoadX509KeyPair(conf.CertFile, conf.KeyFile)
+
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConf.Certificates = []tls.Certificate{keypair}
+	}
+
+	return &tlsConf, nil
+}
@@ -0,0 +1,391 @@
+package clickhouse
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type JSONEncoderConfig struct {
+	// BigIntegersAsNumbers encodes (U)Int64 and wider integers as JSON numbers, they are strings by
+	// default since JavaScript numbers cannot represent them exactly
+	BigIntegersAsNumbers bool
+	// DecimalsAsStrings encodes decimals as strings, they are exact JSON numbers by default
+	DecimalsAsStrings bool
+	// Timezone converts date times to the given location, the column timezone is used when empty
+	Timezone string
+}
+
+// jsonEncoder converts the values scanned by the driver into values that encoding/json renders
+// faithfully, based on the ClickHouse type of their column rather than on their Go type.
+type jsonEncoder struct {
+	conf JSONEncoderConfig
+	loc  *time.Location
+	// locations caches the column timezones, the encoder is shared by concurrent queries
+	locations sync.Map
+}
+
+func newJSONEncoder(conf JSONEncoderConfig) (*jsonEncoder, error) {
+	var enc = jsonEncoder{conf: conf}
+
+	if len(conf.Timezone) > 0 {
+		loc, err := time.LoadLocation(conf.Timezone)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone: %w", err)
+		}
+
+		enc.loc = loc
+	}
+
+	return &enc, nil
+}
+
+// object is a JSON object that keeps its keys in order, e.g. named Tuple elements.
+type object []objectField
+
+type objectField struct {
+	key   string
+	value any
+}
+
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+
+	for i, f := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(f.key)
+
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(f.value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+var (
+	bigIntType  = reflect.TypeOf(big.Int{})
+	decimalType = reflect.TypeOf(decimal.Decimal{})
+	timeType    = reflect.TypeOf(time.Time{})
+	ipType      = reflect.TypeOf(net.IP{})
+)
+
+// encode converts a scanned value, typically a pointer to the column scan type.
+func (enc *jsonEncoder) encode(t *chType, v any) any {
+	return enc.encodeValue(t, reflect.ValueOf(v))
+}
+
+func (enc *jsonEncoder) encodeValue(t *chType, rv reflect.Value) any {
+	for rv.IsValid() && (rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface) {
+		if rv.IsNil() {
+			return nil
+		}
+
+		rv = rv.Elem()
+	}
+
+	if !rv.IsValid() {
+		return nil
+	}
+
+	switch t.name {
+	case "Nullable", "LowCardinality":
+		return enc.encodeValue(t.elems[0], rv)
+	case "SimpleAggregateFunction":
+		return enc.encodeValue(t.elems[1], rv)
+	case "Array":
+		return enc.encodeArray(t.elems[0], rv)
+	case "Nested":
+		return enc.encodeArray(&chType{name: "Tuple", elems: t.elems}, rv)
+	case "Tuple":
+		return enc.encodeTuple(t, rv)
+	case "Map":
+		return enc.encodeMap(t, rv)
+	case "Int8", "Int16", "Int32", "UInt8", "UInt16", "UInt32":
+		return enc.encodeInteger(rv, false)
+	case "Int64", "UInt64", "Int128", "UInt128", "Int256", "UInt256":
+		return enc.encodeInteger(rv, !enc.conf.BigIntegersAsNumbers)
+	case "Float32", "Float64", "BFloat16":
+		return encodeFloat(rv)
+	case "Decimal", "Decimal32", "Decimal64", "Decimal128", "Decimal256":
+		return enc.encodeDecimal(t, rv)
+	case "Bool":
+		if rv.Kind() == reflect.Bool {
+			return rv.Bool()
+		}
+	case "String", "FixedString", "Enum8", "Enum16":
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+			return string(rv.Bytes())
+		}
+
+		if rv.Kind() == reflect.String {
+			return rv.String()
+		}
+	case "UUID", "IPv4", "IPv6":
+		if rv.Type() == ipType {
+			return rv.Interface().(net.IP).String()
+		}
+
+		if s, ok := rv.Interface().(fmt.Stringer); ok {
+			return s.String()
+		}
+	case "Date", "Date32":
+		if rv.Type() == timeType {
+			return rv.Interface().(time.Time).Format(time.DateOnly)
+		}
+	case "DateTime":
+		if rv.Type() == timeType {
+			return enc.encodeTime(rv.Interface().(time.Time), 0, t.params)
+		}
+	case "DateTime64":
+		if rv.Type() == timeType && len(t.params) > 0 {
+			var precision, _ = strconv.Atoi(t.params[0])
+			return enc.encodeTime(rv.Interface().(time.Time), precision, t.params[1:])
+		}
+	case "Nothing":
+		return nil
+	}
+
+	return encodeGeneric(rv)
+}
+
+func (enc *jsonEncoder) encodeArray(elem *chType, rv reflect.Value) any {
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return encodeGeneric(rv)
+	}
+
+	// empty arrays are never null
+	var res = make([]any, rv.Len())
+
+	for i := range res {
+		res[i] = enc.encodeValue(elem, rv.Index(i))
+	}
+
+	return res
+}
+
+// encodeTuple renders named tuples as objects and unnamed ones as arrays, like ClickHouse JSON formats;
+// the driver scans named tuples into maps and unnamed ones into slices.
+func (enc *jsonEncoder) encodeTuple(t *chType, rv reflect.Value) any {
+	var named = len(t.elems) > 0 && !slices.ContainsFunc(t.elems, func(e *chType) bool { return len(e.field) == 0 })
+
+	switch {
+	case rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String:
+		var res = make(object, 0, len(t.elems))
+
+		for _, elem := range t.elems {
+			res = append(res, objectField{
+				key:   elem.field,
+				value: enc.encodeValue(elem, rv.MapIndex(reflect.ValueOf(elem.field).Convert(rv.Type().Key()))),
+			})
+		}
+
+		return res
+	case (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && rv.Len() == len(t.elems):
+		var values = make([]any, len(t.elems))
+
+		for i, elem := range t.elems {
+			values[i] = enc.encodeValue(elem, rv.Index(i))
+		}
+
+		if !named {
+			return values
+		}
+
+		var res = make(object, len(t.elems))
+
+		for i, elem := range t.elems {
+			res[i] = objectField{key: elem.field, value: values[i]}
+		}
+
+		return res
+	default:
+		return encodeGeneric(rv)
+	}
+}
+
+// encodeMap renders maps as objects with keys in ascending order, the driver scans them into Go maps
+// which do not keep the order of ClickHouse.
+func (enc *jsonEncoder) encodeMap(t *chType, rv reflect.Value) any {
+	if rv.Kind() != reflect.Map || len(t.elems) != 2 {
+		return encodeGeneric(rv)
+	}
+
+	var res = make(object, 0, rv.Len())
+
+	for iter := rv.MapRange(); iter.Next(); {
+		res = append(res, objectField{
+			key:   mapKey(enc.encodeValue(t.elems[0], iter.Key())),
+			value: enc.encodeValue(t.elems[1], iter.Value()),
+		})
+	}
+
+	slices.SortFunc(res, func(a, b objectField) int { return strings.Compare(a.key, b.key) })
+	return res
+}
+
+func mapKey(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case nil:
+		return "null"
+	default:
+		js, _ := json.Marshal(v)
+		return string(js)
+	}
+}
+
+func (enc *jsonEncoder) encodeInteger(rv reflect.Value, quoted bool) any {
+	var s string
+
+	switch {
+	case rv.CanInt():
+		s = strconv.FormatInt(rv.Int(), 10)
+	case rv.CanUint():
+		s = strconv.FormatUint(rv.Uint(), 10)
+	case rv.Type() == bigIntType:
+		var n = rv.Interface().(big.Int)
+		s = n.String()
+	default:
+		return encodeGeneric(rv)
+	}
+
+	if quoted {
+		return s
+	}
+
+	return json.Number(s)
+}
+
+// encodeFloat renders NaN and infinities as null, like ClickHouse JSON formats.
+func encodeFloat(rv reflect.Value) any {
+	if !rv.CanFloat() {
+		return encodeGeneric(rv)
+	}
+
+	var f = rv.Float()
+
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil
+	}
+
+	var bitSize = 64
+
+	if rv.Kind() == reflect.Float32 {
+		bitSize = 32
+	}
+
+	return json.Number(strconv.FormatFloat(f, 'g', -1, bitSize))
+}
+
+// encodeDecimal renders decimals with their column scale, e.g. 1.50 for Decimal(9, 2).
+func (enc *jsonEncoder) encodeDecimal(t *chType, rv reflect.Value) any {
+	if rv.Type() != decimalType {
+		return encodeGeneric(rv)
+	}
+
+	var scale string
+
+	switch {
+	case t.name == "Decimal" && len(t.params) == 2:
+		scale = t.params[1]
+	case t.name != "Decimal" && len(t.params) == 1:
+		scale = t.params[0]
+	}
+
+	var (
+		d    = rv.Interface().(decimal.Decimal)
+		s    = d.String()
+		n, _ = strconv.Atoi(scale)
+	)
+
+	if n > 0 {
+		s = d.StringFixed(int32(n))
+	}
+
+	if enc.conf.DecimalsAsStrings {
+		return s
+	}
+
+	return json.Number(s)
+}
+
+// encodeTime renders date times in RFC 3339 with the column precision, in the configured timezone,
+// else in the column timezone.
+func (enc *jsonEncoder) encodeTime(t time.Time, precision int, params []string) string {
+	var loc = enc.loc
+
+	if loc == nil && len(params) > 0 {
+		loc = enc.location(unquote(params[0]))
+	}
+
+	if loc != nil {
+		t = t.In(loc)
+	}
+
+	var layout = "2006-01-02T15:04:05"
+
+	if precision > 0 {
+		layout += "." + strings.Repeat("0", precision)
+	}
+
+	return t.Format(layout + "Z07:00")
+}
+
+func (enc *jsonEncoder) location(name string) *time.Location {
+	if loc, found := enc.locations.Load(name); found {
+		return loc.(*time.Location)
+	}
+
+	// an unknown timezone keeps the one of the scanned value
+	var loc, _ = time.LoadLocation(name)
+	enc.locations.Store(name, loc)
+	return loc
+}
+
+// encodeGeneric handles types the encoder does not know (JSON, Variant, Dynamic, ...) and values
+// that do not have the expected Go type.
+func encodeGeneric(rv reflect.Value) any {
+	switch {
+	case rv.Type() == bigIntType:
+		var n = rv.Interface().(big.Int)
+		return n.String()
+	case rv.Type() == decimalType:
+		return json.Number(rv.Interface().(decimal.Decimal).String())
+	case rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8:
+		return string(rv.Bytes())
+	default:
+		return rv.Interface()
+	}
+}
@@ -0,0 +1,223 @@
+package clickhouse
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"math"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+type encoderColumn struct {
+	typ   string
+	value any
+}
+
+// ptr mimics the driver, which scans into pointers to the column scan type.
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func bigInt(s string) *big.Int {
+	var n, _ = new(big.Int).SetString(s, 10)
+	return n
+}
+
+var (
+	paris = func() *time.Location { loc, _ := time.LoadLocation("Europe/Paris"); return loc }()
+	ts    = time.Date(2024, 3, 5, 14, 7, 9, 123456789, time.UTC)
+)
+
+var encoderCases = []struct {
+	name    string
+	conf    JSONEncoderConfig
+	columns map[string]encoderColumn
+}{
+	{
+		name: "integers",
+		columns: map[string]encoderColumn{
+			"int8":    {"Int8", ptr(int8(-128))},
+			"uint32":  {"UInt32", ptr(uint32(math.MaxUint32))},
+			"int64":   {"Int64", ptr(int64(math.MinInt64))},
+			"uint64":  {"UInt64", ptr(uint64(math.MaxUint64))},
+			"int128":  {"Int128", ptr(bigInt("-170141183460469231731687303715884105728"))},
+			"uint256": {"UInt256", ptr(bigInt("115792089237316195423570985008687907853269984665640564039457584007913129639935"))},
+		},
+	},
+	{
+		name: "integers_as_numbers",
+		conf: JSONEncoderConfig{BigIntegersAsNumbers: true},
+		columns: map[string]encoderColumn{
+			"uint64": {"UInt64", ptr(uint64(math.MaxUint64))},
+			"int128": {"Int128", ptr(bigInt("-170141183460469231731687303715884105728"))},
+		},
+	},
+	{
+		name: "floats",
+		columns: map[string]encoderColumn{
+			"float32": {"Float32", ptr(float32(0.1))},
+			"float64": {"Float64", ptr(1e300)},
+			"nan":     {"Float64", ptr(math.NaN())},
+			"inf":     {"Float64", ptr(math.Inf(-1))},
+		},
+	},
+	{
+		name: "decimals",
+		columns: map[string]encoderColumn{
+			"decimal":    {"Decimal(9, 2)", ptr(decimal.RequireFromString("1.5"))},
+			"decimal64":  {"Decimal64(4)", ptr(decimal.RequireFromString("-12345678.9"))},
+			"decimal256": {"Decimal(76, 0)", ptr(decimal.RequireFromString("12345678901234567890123456789012345678901234567890"))},
+		},
+	},
+	{
+		name: "decimals_as_strings",
+		conf: JSONEncoderConfig{DecimalsAsStrings: true},
+		columns: map[string]encoderColumn{
+			"decimal": {"Decimal(9, 2)", ptr(decimal.RequireFromString("1.5"))},
+		},
+	},
+	{
+		name: "strings",
+		columns: map[string]encoderColumn{
+			"string":       {"String", ptr("é\x00\"")},
+			"fixed_string": {"FixedString(4)", ptr("ab\x00\x00")},
+			"enum8":        {"Enum8('a' = 1, 'b, c' = 2)", ptr("b, c")},
+			"bool":         {"Bool", ptr(true)},
+			"uuid":         {"UUID", ptr(uuid.MustParse("0190a0e2-7b3c-7d1e-8f00-0123456789ab"))},
+			"ipv4":         {"IPv4", ptr(net.ParseIP("192.168.0.1").To4())},
+			"ipv6":         {"IPv6", ptr(net.ParseIP("2001:db8::1"))},
+		},
+	},
+	{
+		name: "dates",
+		columns: map[string]encoderColumn{
+			"date":          {"Date", ptr(time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC))},
+			"date32":        {"Date32", ptr(time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC))},
+			"datetime":      {"DateTime", ptr(ts.Truncate(time.Second))},
+			"datetime_tz":   {"DateTime('Europe/Paris')", ptr(ts.Truncate(time.Second))},
+			"datetime64":    {"DateTime64(3)", ptr(ts.Truncate(time.Millisecond))},
+			"datetime64_tz": {"DateTime64(6, 'Europe/Paris')", ptr(ts.Truncate(time.Microsecond).In(paris))},
+			"datetime64_0":  {"DateTime64(0)", ptr(ts.Truncate(time.Second))},
+		},
+	},
+	{
+		name: "dates_timezone",
+		conf: JSONEncoderConfig{Timezone: "America/New_York"},
+		columns: map[string]encoderColumn{
+			"datetime_tz": {"DateTime('Europe/Paris')", ptr(ts.Truncate(time.Second))},
+			"datetime64":  {"DateTime64(9)", ptr(ts)},
+		},
+	},
+	{
+		name: "nullables",
+		columns: map[string]encoderColumn{
+			"null":           {"Nullable(UInt64)", ptr((*uint64)(nil))},
+			"uint64":         {"Nullable(UInt64)", ptr(ptr(uint64(42)))},
+			"low_card":       {"LowCardinality(Nullable(String))", ptr(ptr("a"))},
+			"low_card_null":  {"LowCardinality(Nullable(String))", ptr((*string)(nil))},
+			"nothing":        {"Nullable(Nothing)", ptr((*any)(nil))},
+			"simple_agg_fun": {"SimpleAggregateFunction(sum, UInt64)", ptr(uint64(7))},
+		},
+	},
+	{
+		name: "arrays",
+		columns: map[string]encoderColumn{
+			"empty":    {"Array(UInt64)", ptr([]uint64(nil))},
+			"uint64":   {"Array(UInt64)", ptr([]uint64{1, math.MaxUint64})},
+			"nullable": {"Array(Nullable(Int32))", ptr([]*int32{ptr(int32(1)), nil})},
+			"nested":   {"Array(Array(String))", ptr([][]string{{"a"}, {}})},
+		},
+	},
+	{
+		name: "maps",
+		columns: map[string]encoderColumn{
+			"string_keys": {"Map(String, UInt64)", ptr(map[string]uint64{"b": 2, "a": 1})},
+			"int_keys":    {"Map(UInt8, Array(Nullable(Float64)))", ptr(map[uint8][]*float64{10: {nil}, 2: {ptr(0.5)}})},
+			"date_keys":   {"Map(Date, String)", ptr(map[time.Time]string{time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC): "x"})},
+		},
+	},
+	{
+		name: "tuples",
+		columns: map[string]encoderColumn{
+			"unnamed": {"Tuple(UInt64, String)", ptr([]any{uint64(math.MaxUint64), "a"})},
+			"named": {"Tuple(z UInt64, `a b` Nullable(String), c Tuple(Decimal(9, 3)))", ptr(map[string]any{
+				"z":   uint64(math.MaxUint64),
+				"a b": (*string)(nil),
+				"c":   []any{decimal.RequireFromString("1")},
+			})},
+			"nested": {"Nested(a UInt8, b String)", ptr([]map[string]any{{"a": uint8(1), "b": "x"}, {"a": uint8(2), "b": "y"}})},
+			"point":  {"Point", ptr([2]float64{1.5, -2})},
+			"ring":   {"Ring", ptr([][2]float64{{0, 0}, {1, 1}})},
+		},
+	},
+	{
+		name: "fallbacks",
+		columns: map[string]encoderColumn{
+			"unknown":     {"JSON", ptr(map[string]any{"a": 1})},
+			"mismatch":    {"UInt64", ptr("42")},
+			"big_int":     {"Dynamic", ptr(bigInt("12345678901234567890"))},
+			"bytes":       {"String", ptr([]byte("abc"))},
+			"nil_pointer": {"String", (*string)(nil)},
+		},
+	},
+}
+
+func TestJSONEncoder(t *testing.T) {
+	for _, tc := range encoderCases {
+		t.Run(tc.name, func(t *testing.T) {
+			enc, err := newJSONEncoder(tc.conf)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var row = make(map[string]any)
+
+			for name, col := range tc.columns {
+				row[name] = enc.encode(parseType(col.typ), col.value)
+			}
+
+			got, err := json.MarshalIndent(row, "", "  ")
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got = append(got, '\n')
+
+			var path = filepath.Join("testdata", "encoder", tc.name+".json")
+
+			if *update {
+				if err := os.WriteFile(path, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(path)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(got, want) {
+				t.Errorf("encoded row does not match %s:\ngot:\n%s\nwant:\n%s", path, got, want)
+			}
+		})
+	}
+}
+
+func TestNewJSONEncoderInvalidTimezone(t *testing.T) {
+	if _, err := newJSONEncoder(JSONEncoderConfig{Timezone: "Nowhere/Invalid"}); err == nil {
+		t.Error("expected an error for an invalid timezone")
+	}
+}
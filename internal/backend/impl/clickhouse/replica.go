@@ -0,0 +1,64 @@
+package clickhouse
+
+import (
+	"context"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/samber/lo"
+)
+
+type replica struct {
+	addr     string
+	conn     driver.Conn
+	inFlight atomic.Int64
+	healthy  atomic.Bool
+}
+
+// candidates returns replicas in the order they must be tried: healthy ones first, ordered
+// according to the selection strategy, then unhealthy ones as a last resort.
+func (b *ClickhouseBackend) candidates() []*replica {
+	var healthy, unhealthy = lo.FilterReject(b.replicas, func(r *replica, _ int) bool { return r.healthy.Load() })
+
+	switch b.conf.Strategy {
+	case ReplicaSelectionRoundRobin:
+		if len(healthy) > 0 {
+			var offset = int(b.next.Add(1) % uint64(len(healthy)))
+			healthy = append(append([]*replica{}, healthy[offset:]...), healthy[:offset]...)
+		}
+
+	case ReplicaSelectionLeastLoaded:
+		var loads = lo.SliceToMap(healthy, func(r *replica) (*replica, int64) { return r, r.inFlight.Load() })
+		sort.SliceStable(healthy, func(i, j int) bool { return loads[healthy[i]] < loads[healthy[j]] })
+	}
+
+	return append(healthy, unhealthy...)
+}
+
+func (b *ClickhouseBackend) healthCheckLoop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(b.conf.HealthCheckInterval):
+			for _, r := range b.replicas {
+				var (
+					ctx, cancel = context.WithTimeout(ctx, b.conf.HealthCheckTimeout)
+					err         = r.conn.Ping(ctx)
+				)
+
+				cancel()
+
+				if wasHealthy := r.healthy.Swap(err == nil); wasHealthy != (err == nil) {
+					if err != nil {
+						b.logger.Warn("clickhouse replica is unhealthy", "addr", r.addr, "error", err.Error())
+					} else {
+						b.logger.Info("clickhouse replica is healthy again", "addr", r.addr)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package clickhouse
+
+import (
+	"strings"
+)
+
+// chType is a parsed ClickHouse type name, as returned by DatabaseTypeName().
+type chType struct {
+	name string
+	// params are the raw parameters, e.g. the precision and timezone of DateTime64(3, 'UTC')
+	params []string
+	// elems are the parsed parameters of composite types (Nullable, Array, Map, Tuple, ...)
+	elems []*chType
+	// field is the element name of named Tuple and Nested elements
+	field string
+}
+
+var compositeTypes = map[string]bool{
+	"Nullable":                true,
+	"LowCardinality":          true,
+	"Array":                   true,
+	"Map":                     true,
+	"Tuple":                   true,
+	"Nested":                  true,
+	"SimpleAggregateFunction": true,
+}
+
+// geoTypes are aliases of composite types, see https://clickhouse.com/docs/en/sql-reference/data-types/geo
+var geoTypes = map[string]string{
+	"Point":           "Tuple(Float64, Float64)",
+	"Ring":            "Array(Tuple(Float64, Float64))",
+	"LineString":      "Array(Tuple(Float64, Float64))",
+	"MultiLineString": "Array(Array(Tuple(Float64, Float64)))",
+	"Polygon":         "Array(Array(Tuple(Float64, Float64)))",
+	"MultiPolygon":    "Array(Array(Array(Tuple(Float64, Float64))))",
+}
+
+func parseType(s string) *chType {
+	s = strings.TrimSpace(s)
+
+	if alias, found := geoTypes[s]; found {
+		return parseType(alias)
+	}
+
+	var open = strings.IndexByte(s, '(')
+
+	if open < 0 || !strings.HasSuffix(s, ")") {
+		return &chType{name: s}
+	}
+
+	var t = chType{
+		name:   strings.TrimSpace(s[:open]),
+		params: splitParams(s[open+1 : len(s)-1]),
+	}
+
+	if !compositeTypes[t.name] {
+		return &t
+	}
+
+	for i, param := range t.params {
+		// the first parameter of SimpleAggregateFunction is the function
+		if t.name == "SimpleAggregateFunction" && i == 0 {
+			t.elems = append(t.elems, &chType{name: param})
+			continue
+		}
+
+		var field, typ = splitField(param)
+		var elem = parseType(typ)
+		elem.field = field
+		t.elems = append(t.elems, elem)
+	}
+
+	return &t
+}
+
+// splitParams splits type parameters on top-level commas, skipping quoted strings.
+func splitParams(s string) []string {
+	var (
+		params []string
+		depth  int
+		quote  byte
+		start  int
+	)
+
+	for i := 0; i < len(s); i++ {
+		var c = s[i]
+
+		switch {
+		case quote != 0 && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '`' || c == '"':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			params = append(params, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+
+	return append(params, strings.TrimSpace(s[start:]))
+}
+
+// splitField splits a named Tuple or Nested element, e.g. "a String" or "`a b` String".
+func splitField(s string) (string, string) {
+	if strings.HasPrefix(s, "`") {
+		if end := strings.IndexByte(s[1:], '`'); end >= 0 {
+			return s[1 : end+1], strings.TrimSpace(s[end+2:])
+		}
+	}
+
+	var end = strings.IndexAny(s, " (")
+
+	// type names never have a space before their parameters
+	if end < 0 || s[end] == '(' {
+		return "", s
+	}
+
+	return s[:end], strings.TrimSpace(s[end+1:])
+}
+
+// unquote returns the value of a string literal parameter, e.g. the timezone of DateTime('UTC').
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return strings.NewReplacer(`\'`, `'`, `\\`, `\`).Replace(s[1 : len(s)-1])
+	}
+
+	return s
+}
@@ -0,0 +1,156 @@
+package duckdb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/agnosticeng/agp/internal/backend/impl/sqldb"
+	"github.com/google/uuid"
+	"github.com/marcboeker/go-duckdb"
+)
+
+// typeNames maps DuckDB type names to their ClickHouse counterpart, so that
+// backend.Schema stays consistent whatever the backend used by a tier.
+var typeNames = map[string]string{
+	"BOOLEAN":      "Bool",
+	"TINYINT":      "Int8",
+	"SMALLINT":     "Int16",
+	"INTEGER":      "Int32",
+	"BIGINT":       "Int64",
+	"HUGEINT":      "Int128",
+	"UTINYINT":     "UInt8",
+	"USMALLINT":    "UInt16",
+	"UINTEGER":     "UInt32",
+	"UBIGINT":      "UInt64",
+	"UHUGEINT":     "UInt128",
+	"FLOAT":        "Float32",
+	"DOUBLE":       "Float64",
+	"VARCHAR":      "String",
+	"BLOB":         "String",
+	"UUID":         "UUID",
+	"DATE":         "Date32",
+	"TIMESTAMP":    "DateTime64(6)",
+	"TIMESTAMP_S":  "DateTime64(0)",
+	"TIMESTAMP_MS": "DateTime64(3)",
+	"TIMESTAMP_NS": "DateTime64(9)",
+	"TIMESTAMPTZ":  "DateTime64(6, 'UTC')",
+	"INTERVAL":     "String",
+	"JSON":         "JSON",
+}
+
+var decimalRegexp = regexp.MustCompile(`^DECIMAL\((\d+),(\d+)\)$`)
+
+type DuckDBBackend struct {
+	*sqldb.SQLBackend
+}
+
+// NewDuckDBBackend opens a DuckDB database, the dsn is either duckdb:// for an in-memory
+// database or duckdb:///path/to/file.db, query parameters are passed as DuckDB config.
+func NewDuckDBBackend(ctx context.Context, dsn string) (*DuckDBBackend, error) {
+	var path = strings.TrimPrefix(strings.TrimPrefix(dsn, "duckdb:"), "//")
+
+	connector, err := duckdb.NewConnector(path, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &DuckDBBackend{
+		sqldb.NewSQLBackend(sql.OpenDB(connector), sqldb.SQLBackendOptions{
+			TypeMapper:  mapType,
+			ValueMapper: mapValue,
+		}),
+	}, nil
+}
+
+func mapType(ct *sql.ColumnType) string {
+	var name = ct.DatabaseTypeName()
+
+	if elem, found := strings.CutSuffix(name, "[]"); found {
+		if typ, found := typeNames[elem]; found {
+			return "Array(" + typ + ")"
+		}
+	}
+
+	if m := decimalRegexp.FindStringSubmatch(name); m != nil {
+		return fmt.Sprintf("Decimal(%s, %s)", m[1], m[2])
+	}
+
+	if typ, found := typeNames[name]; found {
+		return typ
+	}
+
+	return name
+}
+
+func mapValue(ct *sql.ColumnType, v any) any {
+	switch v := v.(type) {
+	case duckdb.Decimal:
+		return decimalNumber(v)
+	case duckdb.UUID:
+		return uuid.UUID(v).String()
+	case []byte:
+		if ct.DatabaseTypeName() == "UUID" && len(v) == 16 {
+			return uuid.UUID(v).String()
+		}
+
+		return v
+	case duckdb.Interval:
+		return fmt.Sprintf("%d months %d days %d microseconds", v.Months, v.Days, v.Micros)
+	case duckdb.Map:
+		var m = make(map[string]any, len(v))
+
+		for k, e := range v {
+			m[fmt.Sprint(k)] = mapValue(ct, e)
+		}
+
+		return m
+	case map[string]any:
+		for k, e := range v {
+			v[k] = mapValue(ct, e)
+		}
+
+		return v
+	case []any:
+		for i, e := range v {
+			v[i] = mapValue(ct, e)
+		}
+
+		return v
+	default:
+		return v
+	}
+}
+
+// decimalNumber renders a decimal exactly with its column scale, e.g. 1.50 for DECIMAL(9, 2), as the
+// ClickHouse backend does.
+func decimalNumber(d duckdb.Decimal) json.Number {
+	if d.Value == nil {
+		return json.Number("0")
+	}
+
+	var (
+		digits = new(big.Int).Abs(d.Value).String()
+		scale  = int(d.Scale)
+		sign   string
+	)
+
+	if d.Value.Sign() < 0 {
+		sign = "-"
+	}
+
+	if scale == 0 {
+		return json.Number(sign + digits)
+	}
+
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+
+	return json.Number(sign + digits[:len(digits)-scale] + "." + digits[len(digits)-scale:])
+}
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/agnosticeng/agp/internal/backend/impl/sqldb"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// typeNames maps PostgreSQL type names to their ClickHouse counterpart, so that
+// backend.Schema stays consistent whatever the backend used by a tier.
+var typeNames = map[string]string{
+	"BOOL":        "Bool",
+	"INT2":        "Int16",
+	"INT4":        "Int32",
+	"INT8":        "Int64",
+	"FLOAT4":      "Float32",
+	"FLOAT8":      "Float64",
+	"NUMERIC":     "Decimal",
+	"TEXT":        "String",
+	"VARCHAR":     "String",
+	"BPCHAR":      "String",
+	"NAME":        "String",
+	"BYTEA":       "String",
+	"UUID":        "UUID",
+	"JSON":        "JSON",
+	"JSONB":       "JSON",
+	"DATE":        "Date32",
+	"TIMESTAMP":   "DateTime64(6)",
+	"TIMESTAMPTZ": "DateTime64(6, 'UTC')",
+	"INET":        "String",
+	"INTERVAL":    "String",
+}
+
+type PostgresBackend struct {
+	*sqldb.SQLBackend
+}
+
+func NewPostgresBackend(ctx context.Context, dsn string) (*PostgresBackend, error) {
+	db, err := sql.Open("pgx", dsn)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &PostgresBackend{
+		sqldb.NewSQLBackend(db, sqldb.SQLBackendOptions{
+			TypeMapper:        mapType,
+			DisableParameters: true,
+		}),
+	}, nil
+}
+
+func mapType(ct *sql.ColumnType) string {
+	var name = ct.DatabaseTypeName()
+
+	// array type names are prefixed with an underscore
+	if elem, found := strings.CutPrefix(name, "_"); found {
+		if typ, found := typeNames[elem]; found {
+			return "Array(" + typ + ")"
+		}
+	}
+
+	if typ, found := typeNames[name]; found {
+		return typ
+	}
+
+	return name
+}
@@ -0,0 +1,165 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/agnosticeng/agp/internal/backend"
+)
+
+// progressInterval throttles progress reports, database/sql drivers do not report
+// progress so it is derived from the number of rows scanned so far.
+const progressInterval = time.Second
+
+type SQLBackendOptions struct {
+	// TypeMapper maps a column type to the type name exposed in backend.Schema.
+	TypeMapper func(*sql.ColumnType) string
+	// ValueMapper converts scanned values to JSON-friendly ones.
+	ValueMapper func(*sql.ColumnType, any) any
+	// DisableParameters makes queries with parameters fail for drivers without named parameters support.
+	DisableParameters bool
+}
+
+type SQLBackend struct {
+	db   *sql.DB
+	opts SQLBackendOptions
+}
+
+func NewSQLBackend(db *sql.DB, opts SQLBackendOptions) *SQLBackend {
+	if opts.TypeMapper == nil {
+		opts.TypeMapper = func(ct *sql.ColumnType) string { return ct.DatabaseTypeName() }
+	}
+
+	if opts.ValueMapper == nil {
+		opts.ValueMapper = func(_ *sql.ColumnType, v any) any { return v }
+	}
+
+	return &SQLBackend{db: db, opts: opts}
+}
+
+// OpenSQLBackend opens a backend on top of any registered database/sql driver.
+func OpenSQLBackend(ctx context.Context, driver string, dsn string) (*SQLBackend, error) {
+	db, err := sql.Open(driver, dsn)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return NewSQLBackend(db, SQLBackendOptions{}), nil
+}
+
+func (b *SQLBackend) ExecuteQuery(ctx context.Context, query string, optfns ...backend.RunOption) (*backend.Result, error) {
+	var (
+		runOpts = backend.BuildRunOptions(optfns...)
+		args    []any
+	)
+
+	if len(runOpts.Settings) > 0 {
+		return nil, fmt.Errorf("query settings are not supported by this backend")
+	}
+
+	if len(runOpts.Parameters) > 0 {
+		if b.opts.DisableParameters {
+			return nil, fmt.Errorf("query parameters are not supported by this backend")
+		}
+
+		for k, v := range runOpts.Parameters {
+			args = append(args, sql.Named(k, v))
+		}
+	}
+
+	var t0 = time.Now()
+
+	queryRes, err := b.db.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer queryRes.Close()
+
+	columnTypes, err := queryRes.ColumnTypes()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		res          = backend.Result{Data: make([]backend.Row, 0)}
+		p            backend.Progress
+		lastProgress = t0
+	)
+
+	for _, ct := range columnTypes {
+		var typ = b.opts.TypeMapper(ct)
+
+		if nullable, ok := ct.Nullable(); ok && nullable {
+			typ = fmt.Sprintf("Nullable(%s)", typ)
+		}
+
+		res.Meta = append(res.Meta, backend.Column{
+			Name: ct.Name(),
+			Type: typ,
+		})
+	}
+
+	for queryRes.Next() {
+		var values = make([]any, len(columnTypes))
+
+		for i := range values {
+			values[i] = new(any)
+		}
+
+		if err := queryRes.Scan(values...); err != nil {
+			return nil, err
+		}
+
+		var row = make(backend.Row, len(values))
+
+		for i, v := range values {
+			row[i] = b.opts.ValueMapper(columnTypes[i], *(v.(*any)))
+		}
+
+		res.Rows++
+		p.Rows++
+
+		if runOpts.RowHandler == nil {
+			res.Data = append(res.Data, row)
+		} else if err := runOpts.RowHandler(res.Meta, row); err != nil {
+			return nil, err
+		}
+
+		if runOpts.ProgressHandler != nil && time.Since(lastProgress) >= progressInterval {
+			lastProgress = time.Now()
+			p.Elapsed = time.Since(t0)
+			runOpts.ProgressHandler(p)
+		}
+	}
+
+	if err := queryRes.Err(); err != nil {
+		return nil, err
+	}
+
+	if runOpts.ProgressHandler != nil {
+		p.Elapsed = time.Since(t0)
+		p.TotalRows = p.Rows
+		runOpts.ProgressHandler(p)
+	}
+
+	return &res, nil
+}
+
+func (b *SQLBackend) Ping(ctx context.Context) error {
+	return b.db.PingContext(ctx)
+}
+
+func (b *SQLBackend) Close() error {
+	return b.db.Close()
+}
@@ -0,0 +1,84 @@
+package health
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/agnosticeng/agp/internal/async_executor"
+	"github.com/agnosticeng/agp/internal/backend"
+	"github.com/agnosticeng/agp/migrations"
+)
+
+func PostgresCheck(aex *async_executor.AsyncExecutor) CheckFunc {
+	return func(ctx context.Context) (any, error) {
+		return nil, aex.Ping(ctx)
+	}
+}
+
+func ResultStorageCheck(aex *async_executor.AsyncExecutor) CheckFunc {
+	return func(ctx context.Context) (any, error) {
+		return nil, aex.PingResultStorage(ctx)
+	}
+}
+
+// SchemaVersionCheck fails when the database schema is behind the migrations embedded in the binary.
+func SchemaVersionCheck(aex *async_executor.AsyncExecutor, versionTable string) CheckFunc {
+	return func(ctx context.Context) (any, error) {
+		expected, err := migrations.Version()
+
+		if err != nil {
+			return nil, err
+		}
+
+		current, err := aex.SchemaVersion(ctx, versionTable)
+
+		if err != nil {
+			return nil, err
+		}
+
+		var details = map[string]any{"current": current, "expected": expected}
+
+		if current < expected {
+			return details, fmt.Errorf("database schema version %d is behind expected version %d", current, expected)
+		}
+
+		return details, nil
+	}
+}
+
+func BackendCheck(bkd backend.Backend) CheckFunc {
+	return func(ctx context.Context) (any, error) {
+		return nil, bkd.Ping(ctx)
+	}
+}
+
+// LeaseCheck reports which identity holds a lease, it only fails when the lease cannot be read.
+func LeaseCheck(aex *async_executor.AsyncExecutor, key string, identity string) CheckFunc {
+	return func(ctx context.Context) (any, error) {
+		lease, err := aex.GetLease(ctx, key)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if lease == nil {
+			return map[string]any{"owned": false}, nil
+		}
+
+		return map[string]any{
+			"owned":       lease.Owner == identity,
+			"owner":       lease.Owner,
+			"end_of_term": lease.EndOfTerm,
+		}, nil
+	}
+}
+
+// AsyncExecutorChecks registers the readiness checks shared by all processes using an AsyncExecutor.
+func (h *Health) AsyncExecutorChecks(aex *async_executor.AsyncExecutor, resultStorage bool) {
+	h.AddReadinessCheck("postgres", PostgresCheck(aex))
+	h.AddReadinessCheck("schema_version", SchemaVersionCheck(aex, h.conf.SchemaVersionTable))
+
+	if resultStorage {
+		h.AddReadinessCheck("result_storage", ResultStorageCheck(aex))
+	}
+}
@@ -0,0 +1,207 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/sourcegraph/conc/iter"
+	slogctx "github.com/veqryn/slog-context"
+)
+
+type Status string
+
+const (
+	StatusUp   Status = "UP"
+	StatusDown Status = "DOWN"
+)
+
+// CheckFunc returns optional details about the checked dependency, and an error when it is unhealthy.
+type CheckFunc func(ctx context.Context) (any, error)
+
+type check struct {
+	name string
+	f    CheckFunc
+}
+
+type CheckResult struct {
+	Status  Status `json:"status"`
+	Details any    `json:"details,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status Status                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+type HealthConfig struct {
+	// Addr is the listen address of a dedicated health server, processes that already serve
+	// HTTP also expose the health endpoints on their own listener.
+	Addr               string
+	Timeout            time.Duration
+	SchemaVersionTable string
+}
+
+// Health aggregates the liveness and readiness checks registered by the components of a process.
+type Health struct {
+	conf      HealthConfig
+	logger    *slog.Logger
+	mu        sync.RWMutex
+	liveness  []check
+	readiness []check
+}
+
+func NewHealth(ctx context.Context, conf HealthConfig) *Health {
+	if conf.Timeout == 0 {
+		conf.Timeout = 5 * time.Second
+	}
+
+	if len(conf.SchemaVersionTable) == 0 {
+		conf.SchemaVersionTable = "agp_schema_version"
+	}
+
+	return &Health{
+		conf:   conf,
+		logger: slogctx.FromCtx(ctx),
+	}
+}
+
+// AddLivenessCheck registers a check that fails only when the process must be restarted.
+func (h *Health) AddLivenessCheck(name string, f CheckFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.liveness = addCheck(h.liveness, name, f)
+}
+
+// AddReadinessCheck registers a check that fails when the process cannot do its job.
+func (h *Health) AddReadinessCheck(name string, f CheckFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.readiness = addCheck(h.readiness, name, f)
+}
+
+// addCheck replaces any check registered under the same name, so that components sharing
+// a dependency in a standalone process do not check it several times.
+func addCheck(checks []check, name string, f CheckFunc) []check {
+	var res = lo.Reject(checks, func(c check, _ int) bool { return c.name == name })
+	return append(res, check{name: name, f: f})
+}
+
+func (h *Health) Liveness(ctx context.Context) Report {
+	h.mu.RLock()
+	var checks = h.liveness
+	h.mu.RUnlock()
+
+	return h.run(ctx, checks)
+}
+
+func (h *Health) Readiness(ctx context.Context) Report {
+	h.mu.RLock()
+	var checks = h.readiness
+	h.mu.RUnlock()
+
+	return h.run(ctx, checks)
+}
+
+func (h *Health) run(ctx context.Context, checks []check) Report {
+	var (
+		report  = Report{Status: StatusUp, Checks: make(map[string]CheckResult)}
+		results = iter.Map(checks, func(c *check) CheckResult {
+			var ctx, cancel = context.WithTimeout(ctx, h.conf.Timeout)
+			defer cancel()
+
+			details, err := c.f(ctx)
+
+			if err != nil {
+				return CheckResult{Status: StatusDown, Details: details, Error: err.Error()}
+			}
+
+			return CheckResult{Status: StatusUp, Details: details}
+		})
+	)
+
+	for i, res := range results {
+		report.Checks[checks[i].name] = res
+
+		if res.Status == StatusDown {
+			report.Status = StatusDown
+		}
+	}
+
+	return report
+}
+
+// Handler serves /livez, /readyz and /healthz (an alias of /readyz), replying 503 when a check is down.
+func (h *Health) Handler() http.Handler {
+	var mux = http.NewServeMux()
+
+	mux.HandleFunc("/livez", func(w http.ResponseWriter, r *http.Request) {
+		h.writeReport(w, h.Liveness(r.Context()))
+	})
+
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		h.writeReport(w, h.Readiness(r.Context()))
+	})
+
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		h.writeReport(w, h.Readiness(r.Context()))
+	})
+
+	return mux
+}
+
+func (h *Health) Mount(mux *http.ServeMux) {
+	var handler = h.Handler()
+
+	mux.Handle("/livez", handler)
+	mux.Handle("/readyz", handler)
+	mux.Handle("/healthz", handler)
+}
+
+func (h *Health) writeReport(w http.ResponseWriter, report Report) {
+	for name, res := range report.Checks {
+		if res.Status == StatusDown {
+			h.logger.Warn("health check failed", "check", name, "error", res.Error)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if report.Status != StatusUp {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	json.NewEncoder(w).Encode(report)
+}
+
+// Serve runs the dedicated health server until ctx is canceled, it is a no-op when no address is configured.
+func (h *Health) Serve(ctx context.Context) error {
+	if len(h.conf.Addr) == 0 {
+		return nil
+	}
+
+	var httpServer = http.Server{
+		Addr:    h.conf.Addr,
+		Handler: h.Handler(),
+	}
+
+	go func() {
+		<-ctx.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		httpServer.Shutdown(ctx)
+	}()
+
+	h.logger.Info("health server running", "addr", h.conf.Addr)
+
+	if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+
+	return nil
+}
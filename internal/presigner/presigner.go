@@ -0,0 +1,103 @@
+package presigner
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+type PresignOptions struct {
+	Expiration      time.Duration
+	ContentType     string
+	ContentEncoding string
+}
+
+// Presigner returns a time-limited URL giving direct read access to an object; ok is false when the
+// object store does not support it, in which case the object must be proxied.
+type Presigner func(u *url.URL, opts PresignOptions) (string, bool, error)
+
+type S3PresignerConfig struct {
+	AccessKeyId     string
+	SecretAccessKey string
+	SessionToken    string
+	Endpoint        string
+	Region          string
+	DisableSsl      bool
+	ForcePathStyle  bool
+}
+
+type PresignerConfig struct {
+	Enable     bool
+	Expiration time.Duration
+	S3         S3PresignerConfig
+}
+
+// NewPresigner presigns s3:// URLs with the given credentials, which must match those of the object store.
+func NewPresigner(conf PresignerConfig) (Presigner, error) {
+	if conf.Expiration == 0 {
+		conf.Expiration = 5 * time.Minute
+	}
+
+	var awsConf = aws.NewConfig().
+		WithRegion(conf.S3.Region).
+		WithDisableSSL(conf.S3.DisableSsl).
+		WithS3ForcePathStyle(conf.S3.ForcePathStyle)
+
+	if len(conf.S3.Region) == 0 {
+		awsConf = awsConf.WithRegion("auto")
+	}
+
+	if len(conf.S3.AccessKeyId) > 0 {
+		awsConf = awsConf.WithCredentials(credentials.NewStaticCredentials(conf.S3.AccessKeyId, conf.S3.SecretAccessKey, conf.S3.SessionToken))
+	}
+
+	if len(conf.S3.Endpoint) > 0 {
+		awsConf = awsConf.WithEndpoint(conf.S3.Endpoint)
+	}
+
+	sess, err := session.NewSession(awsConf)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 session: %w", err)
+	}
+
+	var svc = s3.New(sess)
+
+	return func(u *url.URL, opts PresignOptions) (string, bool, error) {
+		if u.Scheme != "s3" {
+			return "", false, nil
+		}
+
+		var input = s3.GetObjectInput{
+			Bucket: aws.String(u.Host),
+			Key:    aws.String(strings.TrimPrefix(u.Path, "/")),
+		}
+
+		if len(opts.ContentType) > 0 {
+			input.ResponseContentType = aws.String(opts.ContentType)
+		}
+
+		if len(opts.ContentEncoding) > 0 {
+			input.ResponseContentEncoding = aws.String(opts.ContentEncoding)
+		}
+
+		if opts.Expiration <= 0 || opts.Expiration > conf.Expiration {
+			opts.Expiration = conf.Expiration
+		}
+
+		req, _ := svc.GetObjectRequest(&input)
+		presigned, err := req.Presign(opts.Expiration)
+
+		if err != nil {
+			return "", false, err
+		}
+
+		return presigned, true, nil
+	}, nil
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/agnosticeng/agp/internal/async_executor"
+	"github.com/agnosticeng/agp/internal/health"
 	slogctx "github.com/veqryn/slog-context"
 	"golang.org/x/sync/errgroup"
 )
@@ -14,7 +15,13 @@ type BookkeeperConfig struct {
 	FailDeadInterval time.Duration
 }
 
-func Bookkeeper(ctx context.Context, aex *async_executor.AsyncExecutor, identity string, conf BookkeeperConfig) error {
+func Bookkeeper(
+	ctx context.Context,
+	aex *async_executor.AsyncExecutor,
+	identity string,
+	h *health.Health,
+	conf BookkeeperConfig,
+) error {
 	var (
 		group, groupctx = errgroup.WithContext(ctx)
 		logger          = slogctx.FromCtx(ctx)
@@ -24,6 +31,9 @@ func Bookkeeper(ctx context.Context, aex *async_executor.AsyncExecutor, identity
 		conf.FailDeadInterval = time.Second * 10
 	}
 
+	h.AsyncExecutorChecks(aex, false)
+	h.AddReadinessCheck("lease:FAIL_DEAD", health.LeaseCheck(aex, "FAIL_DEAD", identity))
+
 	group.Go(func() error {
 		return loop(
 			groupctx,
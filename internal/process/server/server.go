@@ -10,11 +10,18 @@ import (
 
 	"github.com/NYTimes/gziphandler"
 	v1 "github.com/agnosticeng/agp/internal/api/v1"
+	"github.com/agnosticeng/agp/internal/api/v1/admin"
 	"github.com/agnosticeng/agp/internal/api/v1/async"
 	"github.com/agnosticeng/agp/internal/api/v1/chproxy"
 	"github.com/agnosticeng/agp/internal/api/v1/sync"
 	"github.com/agnosticeng/agp/internal/async_executor"
+	"github.com/agnosticeng/agp/internal/audit"
 	backend_impl "github.com/agnosticeng/agp/internal/backend/impl"
+	"github.com/agnosticeng/agp/internal/backend/impl/clickhouse"
+	"github.com/agnosticeng/agp/internal/health"
+	"github.com/agnosticeng/agp/internal/presigner"
+	"github.com/agnosticeng/agp/internal/query_hasher"
+	"github.com/agnosticeng/agp/internal/rate_limiter"
 	"github.com/agnosticeng/agp/internal/signer"
 	"github.com/agnosticeng/agp/pkg/client_ip_middleware"
 	"github.com/agnosticeng/agp/pkg/openapi3_auth"
@@ -22,6 +29,7 @@ import (
 	"github.com/getkin/kin-openapi/openapi3filter"
 	"github.com/joemiller/certin"
 	oapi_middleware "github.com/oapi-codegen/nethttp-middleware"
+	strictnethttp "github.com/oapi-codegen/runtime/strictmiddleware/nethttp"
 	"github.com/rs/cors"
 	"github.com/samber/lo"
 	"github.com/swaggest/swgui/v5emb"
@@ -29,12 +37,16 @@ import (
 )
 
 type AsyncAPIConfig struct {
-	Enable bool
+	Enable    bool
+	Retention []async.RetentionTierConfig
+	Redirect  presigner.PresignerConfig
 }
 
 type SyncAPIConfig struct {
 	Enable   bool
 	Backends []BackendTierConfig
+	Query    sync.QueryConfig
+	Tiers    []sync.TierConfig
 }
 
 type CHProxyAPIConfig struct {
@@ -42,15 +54,22 @@ type CHProxyAPIConfig struct {
 	Backends []BackendTierConfig
 }
 
+type AdminAPIConfig struct {
+	Enable bool
+	Secret string
+}
+
 type APIConfig struct {
 	Sync    SyncAPIConfig
 	Async   AsyncAPIConfig
 	ChProxy CHProxyAPIConfig
+	Admin   AdminAPIConfig
 }
 
 type BackendTierConfig struct {
-	Tier string
-	Dsn  string
+	Tier       string
+	Dsn        string
+	Clickhouse clickhouse.ClickhouseBackendConfig
 }
 
 type TLSConfig struct {
@@ -65,11 +84,18 @@ type ServerConfig struct {
 	Jwt         openapi3_auth.OpenAPI3JWTConfig
 	Api         APIConfig
 	Tls         *TLSConfig
+	RateLimit   rate_limiter.RateLimiterConfig
 	DisableCors bool
 	DisableGzip bool
 }
 
-func Server(ctx context.Context, aex *async_executor.AsyncExecutor, conf ServerConfig) error {
+func Server(
+	ctx context.Context,
+	aex *async_executor.AsyncExecutor,
+	auditSink audit.Sink,
+	checks *health.Health,
+	conf ServerConfig,
+) error {
 	var (
 		logger           = slogctx.FromCtx(ctx)
 		mux              = http.NewServeMux()
@@ -81,14 +107,59 @@ func Server(ctx context.Context, aex *async_executor.AsyncExecutor, conf ServerC
 		return err
 	}
 
+	var (
+		requestsMiddleware          = func(h http.Handler) http.Handler { return h }
+		concurrentQueriesMiddleware = func(h http.Handler) http.Handler { return h }
+		dailyQuotaMiddleware        = func(h http.Handler) http.Handler { return h }
+		asyncStrictMiddlewares      []strictnethttp.StrictHTTPMiddlewareFunc
+	)
+
+	if conf.RateLimit.Enable {
+		rl, err := rate_limiter.NewRateLimiter(ctx, aex, conf.RateLimit)
+
+		if err != nil {
+			return err
+		}
+
+		requestsMiddleware = rl.Requests
+		concurrentQueriesMiddleware = rl.ConcurrentQueries
+		dailyQuotaMiddleware = rl.DailyQuota
+		asyncStrictMiddlewares = append(
+			asyncStrictMiddlewares,
+			rl.DailyQuotaOperations("PostExecutions"),
+			rl.InFlightExecutions("PostExecutions"),
+		)
+	}
+
+	if auditSink == nil {
+		auditSink = audit.NopSink{}
+	}
+
+	if aex != nil {
+		checks.AsyncExecutorChecks(aex, conf.Api.Async.Enable)
+	}
+
 	if conf.Api.Async.Enable {
 		if aex == nil {
 			return fmt.Errorf("AsyncExecutor must be provided for async API to work")
 		}
 
+		asyncStrictMiddlewares = append(asyncStrictMiddlewares, async.AuditMiddleware(logger, auditSink, aex.GetQueryHasher()))
+
 		var validationMiddleware = validationMiddleware(swaggerWithServer(lo.Must(async.GetSwagger()), "/v1/async"), jwtAuthFunc)
-		var strictHandler = async.NewStrictHandler(async.NewServer(ctx, sig, aex), nil)
+		var presign presigner.Presigner
+
+		if conf.Api.Async.Redirect.Enable {
+			presign, err = presigner.NewPresigner(conf.Api.Async.Redirect)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		var strictHandler = async.NewStrictHandler(async.NewServer(ctx, sig, presign, aex, conf.Api.Async.Retention), asyncStrictMiddlewares)
 		var handler = async.HandlerWithOptions(strictHandler, async.StdHTTPServerOptions{BaseURL: "/v1/async"})
+		handler = requestsMiddleware(handler)
 		handler = validationMiddleware(handler)
 		handler = client_ip_middleware.ClientIP(handler)
 		mux.Handle("/v1/async/spec.json", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -102,19 +173,23 @@ func Server(ctx context.Context, aex *async_executor.AsyncExecutor, conf ServerC
 		var bkds []sync.BackendTier
 
 		for _, backend := range conf.Api.Sync.Backends {
-			bkd, err := backend_impl.NewBackend(ctx, backend.Dsn)
+			bkd, err := backend_impl.NewBackend(ctx, backend_impl.BackendConfig{
+				Dsn:        backend.Dsn,
+				Clickhouse: backend.Clickhouse,
+			})
 
 			if err != nil {
 				return fmt.Errorf("failed to create backend for tier %s: %w", backend.Tier, err)
 			}
 
 			defer bkd.Close()
+			checks.AddReadinessCheck("sync_backend:"+backend.Tier, health.BackendCheck(bkd))
 			bkds = append(bkds, sync.BackendTier{Tier: backend.Tier, Backend: bkd})
 		}
 
 		var validationMiddleware = validationMiddleware(swaggerWithServer(lo.Must(sync.GetSwagger()), "/v1/sync"), jwtAuthFunc)
 
-		var server, err = sync.NewServer(ctx, bkds)
+		var server, err = sync.NewServer(ctx, bkds, aex, conf.Api.Sync.Query, conf.Api.Sync.Tiers)
 
 		if err != nil {
 			return err
@@ -122,6 +197,10 @@ func Server(ctx context.Context, aex *async_executor.AsyncExecutor, conf ServerC
 
 		var strictHandler = sync.NewStrictHandler(server, nil)
 		var handler = sync.HandlerWithOptions(strictHandler, sync.StdHTTPServerOptions{BaseURL: "/v1/sync"})
+		handler = concurrentQueriesMiddleware(handler)
+		handler = dailyQuotaMiddleware(handler)
+		handler = requestsMiddleware(handler)
+		handler = audit.Middleware(logger, auditSink, audit.SourceSync, query_hasher.SHA256QueryHasher)(handler)
 		handler = validationMiddleware(handler)
 		handler = client_ip_middleware.ClientIP(handler)
 		mux.Handle("/v1/sync/spec.json", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { json.NewEncoder(w).Encode(lo.Must(sync.GetSwagger())) }))
@@ -138,13 +217,23 @@ func Server(ctx context.Context, aex *async_executor.AsyncExecutor, conf ServerC
 
 		var validationMiddleware = validationMiddleware(swaggerWithServer(lo.Must(chproxy.GetSwagger()), "/v1/chproxy"), jwtAuthFunc)
 
-		var server, err = chproxy.NewServer(ctx, bkds)
+		var server, err = chproxy.NewServer(ctx, bkds, aex)
 
 		if err != nil {
 			return err
 		}
 
+		for _, backend := range conf.Api.ChProxy.Backends {
+			checks.AddReadinessCheck("chproxy_backend:"+backend.Tier, func(ctx context.Context) (any, error) {
+				return nil, server.Ping(ctx, backend.Tier)
+			})
+		}
+
 		var handler = chproxy.HandlerWithOptions(server, chproxy.StdHTTPServerOptions{BaseURL: "/v1/chproxy"})
+		handler = concurrentQueriesMiddleware(handler)
+		handler = dailyQuotaMiddleware(handler)
+		handler = requestsMiddleware(handler)
+		handler = audit.Middleware(logger, auditSink, audit.SourceChProxy, query_hasher.SHA256QueryHasher)(handler)
 		handler = validationMiddleware(handler)
 		handler = client_ip_middleware.ClientIP(handler)
 		mux.Handle("/v1/chproxy/spec.json", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { json.NewEncoder(w).Encode(lo.Must(chproxy.GetSwagger())) }))
@@ -152,6 +241,31 @@ func Server(ctx context.Context, aex *async_executor.AsyncExecutor, conf ServerC
 		mux.Handle("/v1/chproxy/", handler)
 	}
 
+	if conf.Api.Admin.Enable {
+		if aex == nil {
+			return fmt.Errorf("AsyncExecutor must be provided for admin API to work")
+		}
+
+		if len(conf.Api.Admin.Secret) == 0 {
+			return fmt.Errorf("a secret must be provided for admin API to work")
+		}
+
+		var validationMiddleware = validationMiddleware(
+			swaggerWithServer(lo.Must(admin.GetSwagger()), "/v1/admin"),
+			openapi3_auth.OpenAPI3Secret(conf.Api.Admin.Secret, openapi3_auth.OpenAPI3SecretConfig{}),
+		)
+
+		var strictHandler = admin.NewStrictHandler(admin.NewServer(ctx, aex), nil)
+		var handler = admin.HandlerWithOptions(strictHandler, admin.StdHTTPServerOptions{BaseURL: "/v1/admin"})
+		handler = validationMiddleware(handler)
+		handler = client_ip_middleware.ClientIP(handler)
+		mux.Handle("/v1/admin/spec.json", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { json.NewEncoder(w).Encode(lo.Must(admin.GetSwagger())) }))
+		mux.Handle("/v1/admin/docs/", v5emb.New("AGP Admin API", "/v1/admin/spec.json", "/v1/admin/docs/"))
+		mux.Handle("/v1/admin/", handler)
+	}
+
+	checks.Mount(mux)
+
 	if len(conf.Addr) == 0 {
 		conf.Addr = "0.0.0.0:8888"
 	}
@@ -159,7 +273,17 @@ func Server(ctx context.Context, aex *async_executor.AsyncExecutor, conf ServerC
 	var h http.Handler = mux
 
 	if !conf.DisableGzip {
-		h = gziphandler.GzipHandler(h)
+		var gzipped, plain = gziphandler.GzipHandler(h), h
+
+		// partial responses must be sent as stored, compressing them would break byte ranges
+		h = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if len(r.Header.Get("Range")) > 0 {
+				plain.ServeHTTP(w, r)
+				return
+			}
+
+			gzipped.ServeHTTP(w, r)
+		})
 	}
 
 	if !conf.DisableCors {
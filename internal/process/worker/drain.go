@@ -0,0 +1,39 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+type DrainState string
+
+const (
+	DrainStateRunning  DrainState = "RUNNING"
+	DrainStateDraining DrainState = "DRAINING"
+	DrainStateDrained  DrainState = "DRAINED"
+)
+
+type drainState struct {
+	atomic.Value
+}
+
+func (s *drainState) Load() DrainState {
+	return s.Value.Load().(DrainState)
+}
+
+func (s *drainState) Store(state DrainState) {
+	s.Value.Store(state)
+}
+
+// check makes the worker unready as soon as it starts draining, so that orchestrators can wait
+// for the DRAINED state reported in the check details.
+func (s *drainState) check(ctx context.Context) (any, error) {
+	var state = s.Load()
+
+	if state != DrainStateRunning {
+		return map[string]any{"state": state}, fmt.Errorf("worker is %s", state)
+	}
+
+	return map[string]any{"state": state}, nil
+}
@@ -7,24 +7,36 @@ import (
 
 	"github.com/agnosticeng/agp/internal/async_executor"
 	backend_impl "github.com/agnosticeng/agp/internal/backend/impl"
+	"github.com/agnosticeng/agp/internal/backend/impl/clickhouse"
+	"github.com/agnosticeng/agp/internal/health"
 	"github.com/samber/lo"
 	slogctx "github.com/veqryn/slog-context"
 	"golang.org/x/sync/errgroup"
 )
 
 type BackendTier struct {
-	Tier  string
-	Count int
-	Dsn   string
+	Tier       string
+	Count      int
+	Dsn        string
+	Clickhouse clickhouse.ClickhouseBackendConfig
 }
 
 type WorkerConfig struct {
 	PollInterval         time.Duration
 	MaxHeartbeatInterval time.Duration
+	DrainTimeout         time.Duration
 	Backends             []BackendTier
 }
 
-func Worker(ctx context.Context, aex *async_executor.AsyncExecutor, identity string, conf WorkerConfig) error {
+// Worker runs executions until ctx is canceled, then drains: it stops picking new executions and
+// lets running ones complete for up to DrainTimeout, after which the remaining ones are requeued.
+func Worker(
+	ctx context.Context,
+	aex *async_executor.AsyncExecutor,
+	identity string,
+	h *health.Health,
+	conf WorkerConfig,
+) error {
 	var logger = slogctx.FromCtx(ctx)
 
 	if len(conf.Backends) == 0 {
@@ -43,27 +55,45 @@ func Worker(ctx context.Context, aex *async_executor.AsyncExecutor, identity str
 		conf.MaxHeartbeatInterval = 10 * time.Second
 	}
 
+	if conf.DrainTimeout == 0 {
+		conf.DrainTimeout = 30 * time.Second
+	}
+
 	logger.Info(
 		"worker starting",
 		"identity", identity,
 		"poll_interval", conf.PollInterval,
 		"max_heartbeat_interval", conf.MaxHeartbeatInterval,
+		"drain_timeout", conf.DrainTimeout,
 	)
 
-	var group, groupctx = errgroup.WithContext(ctx)
+	var (
+		state              drainState
+		group              errgroup.Group
+		runCtx, cancelRuns = context.WithCancelCause(context.WithoutCancel(ctx))
+	)
+
+	defer cancelRuns(nil)
+	state.Store(DrainStateRunning)
+	h.AsyncExecutorChecks(aex, true)
+	h.AddReadinessCheck("drain", state.check)
 
 	for _, backend := range conf.Backends {
 		if backend.Count <= 0 {
 			backend.Count = 1
 		}
 
-		bkd, err := backend_impl.NewBackend(ctx, backend.Dsn)
+		bkd, err := backend_impl.NewBackend(ctx, backend_impl.BackendConfig{
+			Dsn:        backend.Dsn,
+			Clickhouse: backend.Clickhouse,
+		})
 
 		if err != nil {
 			return err
 		}
 
 		defer bkd.Close()
+		h.AddReadinessCheck("backend:"+backend.Tier, health.BackendCheck(bkd))
 
 		for i := 0; i < backend.Count; i++ {
 			group.Go(func() error {
@@ -75,11 +105,11 @@ func Worker(ctx context.Context, aex *async_executor.AsyncExecutor, identity str
 
 				for {
 					select {
-					case <-groupctx.Done():
+					case <-ctx.Done():
 						return nil
 
 					case <-time.After(nextPollInterval):
-						run, err := aex.Run(groupctx, workerId, bkd, async_executor.RunOptions{
+						run, err := aex.Run(runCtx, workerId, bkd, async_executor.RunOptions{
 							MaxHeartbeatInterval: conf.MaxHeartbeatInterval,
 							Tier:                 backend.Tier,
 						})
@@ -106,5 +136,30 @@ func Worker(ctx context.Context, aex *async_executor.AsyncExecutor, identity str
 		}
 	}
 
-	return group.Wait()
+	var done = make(chan error, 1)
+
+	go func() { done <- group.Wait() }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+	}
+
+	state.Store(DrainStateDraining)
+	logger.Info("worker draining", "drain_timeout", conf.DrainTimeout)
+
+	select {
+	case err := <-done:
+		state.Store(DrainStateDrained)
+		logger.Info("worker drained")
+		return err
+
+	case <-time.After(conf.DrainTimeout):
+		logger.Warn("drain timeout reached, requeuing running executions")
+		cancelRuns(async_executor.ErrWorkerDrained)
+		var err = <-done
+		state.Store(DrainStateDrained)
+		return err
+	}
 }
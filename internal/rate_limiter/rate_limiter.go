@@ -0,0 +1,279 @@
+package rate_limiter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	v1 "github.com/agnosticeng/agp/internal/api/v1"
+	"github.com/agnosticeng/agp/internal/async_executor"
+	"github.com/google/uuid"
+	strictnethttp "github.com/oapi-codegen/runtime/strictmiddleware/nethttp"
+	"github.com/samber/lo"
+	slogctx "github.com/veqryn/slog-context"
+)
+
+type TierConfig struct {
+	Tier                  string
+	RequestsPerSecond     float64
+	Burst                 int
+	MaxInFlightExecutions int
+	MaxConcurrentQueries  int
+	MaxDailyRowsRead      int64
+	MaxDailyBytesRead     int64
+	MaxDailyElapsed       time.Duration
+}
+
+func (t TierConfig) hasDailyQuota() bool {
+	return t.MaxDailyRowsRead > 0 || t.MaxDailyBytesRead > 0 || t.MaxDailyElapsed > 0
+}
+
+type RateLimiterConfig struct {
+	Enable     bool
+	Tiers      []TierConfig
+	SlotTTL    time.Duration
+	RetryAfter time.Duration
+}
+
+type RateLimiter struct {
+	conf   RateLimiterConfig
+	logger *slog.Logger
+	aex    *async_executor.AsyncExecutor
+}
+
+func NewRateLimiter(
+	ctx context.Context,
+	aex *async_executor.AsyncExecutor,
+	conf RateLimiterConfig,
+) (*RateLimiter, error) {
+	if aex == nil {
+		return nil, fmt.Errorf("AsyncExecutor must be provided for rate limiter to work")
+	}
+
+	if len(lo.FindDuplicatesBy(conf.Tiers, func(t TierConfig) string { return t.Tier })) > 0 {
+		return nil, fmt.Errorf("config has duplicate tier entries")
+	}
+
+	if conf.SlotTTL == 0 {
+		conf.SlotTTL = time.Minute
+	}
+
+	if conf.RetryAfter == 0 {
+		conf.RetryAfter = time.Second
+	}
+
+	return &RateLimiter{
+		conf:   conf,
+		logger: slogctx.FromCtx(ctx),
+		aex:    aex,
+	}, nil
+}
+
+func (rl *RateLimiter) tierConfig(tier string) (TierConfig, bool) {
+	return lo.Find(rl.conf.Tiers, func(t TierConfig) bool { return t.Tier == tier })
+}
+
+// Requests enforces the requests/sec token bucket of the caller's quota key.
+// It must be mounted behind the validation middleware so that claims are available.
+func (rl *RateLimiter) Requests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			claims      = v1.ClaimsFromContext(r.Context())
+			tier, found = rl.tierConfig(claims.Tier)
+		)
+
+		if !found || tier.RequestsPerSecond <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		allowed, retryAfter, err := rl.aex.TakeToken(r.Context(), claims.QuotaKey, async_executor.TakeTokenOptions{
+			RequestsPerSecond: tier.RequestsPerSecond,
+			Burst:             tier.Burst,
+		})
+
+		if err != nil {
+			httpError(rl.logger, w, err, http.StatusInternalServerError)
+			return
+		}
+
+		if !allowed {
+			tooManyRequests(w, retryAfter, "rate limit exceeded for quota key: %s", claims.QuotaKey)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ConcurrentQueries holds one of the caller's concurrency slots for the whole request duration.
+func (rl *RateLimiter) ConcurrentQueries(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			claims      = v1.ClaimsFromContext(r.Context())
+			tier, found = rl.tierConfig(claims.Tier)
+		)
+
+		if !found || tier.MaxConcurrentQueries <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		slot, err := rl.aex.AcquireConcurrencySlot(
+			r.Context(),
+			claims.QuotaKey,
+			uuid.Must(uuid.NewV7()).String(),
+			async_executor.AcquireConcurrencySlotOptions{
+				Limit: tier.MaxConcurrentQueries,
+				TTL:   rl.conf.SlotTTL,
+			},
+		)
+
+		if err != nil {
+			httpError(rl.logger, w, err, http.StatusInternalServerError)
+			return
+		}
+
+		if slot == nil {
+			tooManyRequests(w, rl.conf.RetryAfter, "too many concurrent queries for quota key: %s", claims.QuotaKey)
+			return
+		}
+
+		var ctx, cancel = context.WithCancel(context.Background())
+
+		defer func() {
+			cancel()
+
+			if err := rl.aex.ReleaseConcurrencySlot(context.Background(), slot.Id); err != nil {
+				rl.logger.Error(err.Error(), "slot_id", slot.Id)
+			}
+		}()
+
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(rl.conf.SlotTTL / 2):
+					if err := rl.aex.RefreshConcurrencySlot(ctx, slot.Id, rl.conf.SlotTTL); err != nil {
+						rl.logger.Error(err.Error(), "slot_id", slot.Id)
+					}
+				}
+			}
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// InFlightExecutions rejects the executions created by the given strict operations when the caller
+// already has too many PENDING or RUNNING executions, the limit being checked as they are created.
+func (rl *RateLimiter) InFlightExecutions(operationIds ...string) strictnethttp.StrictHTTPMiddlewareFunc {
+	return func(f strictnethttp.StrictHTTPHandlerFunc, operationId string) strictnethttp.StrictHTTPHandlerFunc {
+		if !lo.Contains(operationIds, operationId) {
+			return f
+		}
+
+		return func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+			var (
+				claims      = v1.ClaimsFromContext(ctx)
+				tier, found = rl.tierConfig(claims.Tier)
+			)
+
+			if !found || tier.MaxInFlightExecutions <= 0 {
+				return f(ctx, w, r, request)
+			}
+
+			res, err := f(async_executor.WithInFlightLimit(ctx, tier.MaxInFlightExecutions), w, r, request)
+
+			if errors.Is(err, async_executor.ErrTooManyInFlightExecutions) {
+				tooManyRequests(w, rl.conf.RetryAfter, "too many in-flight executions for quota key: %s", claims.QuotaKey)
+				return nil, nil
+			}
+
+			return res, err
+		}
+	}
+}
+
+// DailyQuota rejects requests of callers that exhausted their daily quota, as recorded in the usage ledger.
+func (rl *RateLimiter) DailyQuota(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var claims = v1.ClaimsFromContext(r.Context())
+
+		exceeded, retryAfter, err := rl.dailyQuotaExceeded(r.Context(), claims)
+
+		if err != nil {
+			httpError(rl.logger, w, err, http.StatusInternalServerError)
+			return
+		}
+
+		if exceeded {
+			tooManyRequests(w, retryAfter, "daily quota exceeded for quota key: %s", claims.QuotaKey)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// DailyQuotaOperations is the strict middleware counterpart of DailyQuota, restricted to the given operations.
+func (rl *RateLimiter) DailyQuotaOperations(operationIds ...string) strictnethttp.StrictHTTPMiddlewareFunc {
+	return func(f strictnethttp.StrictHTTPHandlerFunc, operationId string) strictnethttp.StrictHTTPHandlerFunc {
+		if !lo.Contains(operationIds, operationId) {
+			return f
+		}
+
+		return func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+			var claims = v1.ClaimsFromContext(ctx)
+
+			exceeded, retryAfter, err := rl.dailyQuotaExceeded(ctx, claims)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if exceeded {
+				tooManyRequests(w, retryAfter, "daily quota exceeded for quota key: %s", claims.QuotaKey)
+				return nil, nil
+			}
+
+			return f(ctx, w, r, request)
+		}
+	}
+}
+
+func (rl *RateLimiter) dailyQuotaExceeded(ctx context.Context, claims *v1.Claims) (bool, time.Duration, error) {
+	var tier, found = rl.tierConfig(claims.Tier)
+
+	if !found || !tier.hasDailyQuota() {
+		return false, 0, nil
+	}
+
+	usage, err := rl.aex.DailyUsage(ctx, claims.QuotaKey)
+
+	if err != nil {
+		return false, 0, err
+	}
+
+	var exceeded = (tier.MaxDailyRowsRead > 0 && usage.RowsRead >= tier.MaxDailyRowsRead) ||
+		(tier.MaxDailyBytesRead > 0 && usage.BytesRead >= tier.MaxDailyBytesRead) ||
+		(tier.MaxDailyElapsed > 0 && time.Duration(usage.ElapsedMs)*time.Millisecond >= tier.MaxDailyElapsed)
+
+	return exceeded, time.Until(usage.Period.Add(time.Hour * 24)), nil
+}
+
+func tooManyRequests(w http.ResponseWriter, retryAfter time.Duration, format string, args ...any) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Max(1, math.Ceil(retryAfter.Seconds())))))
+	http.Error(w, fmt.Sprintf(format, args...), http.StatusTooManyRequests)
+}
+
+func httpError(logger *slog.Logger, w http.ResponseWriter, err error, statusCode int) {
+	logger.Error(err.Error(), "status_code", statusCode)
+	http.Error(w, err.Error(), statusCode)
+}
@@ -0,0 +1,14 @@
+package utils
+
+import "io"
+
+type CountingWriter struct {
+	io.Writer
+	N int64
+}
+
+func (w *CountingWriter) Write(p []byte) (int, error) {
+	n, err := w.Writer.Write(p)
+	w.N += int64(n)
+	return n, err
+}
@@ -0,0 +1,29 @@
+-- Create rate limiting tables shared by all server replicas
+
+-- one GCRA bucket per quota key: tat is the theoretical arrival time of the next request
+create table agp_rate_limit (
+    key text primary key,
+    tat timestamp with time zone not null
+);
+
+-- one row per in-flight sync/chproxy query, expired rows are ignored and purged lazily
+create table agp_concurrency_slot (
+    id bigserial primary key,
+    key text not null,
+    owner text not null,
+    expires_at timestamp with time zone not null
+);
+
+create index idx_agp_concurrency_slot_key
+on agp_concurrency_slot (key, expires_at);
+
+-- ensures in-flight executions of a given creator can be counted fast
+create index idx_agp_execution_created_by_in_flight
+on agp_execution (created_by)
+where status in ('PENDING', 'RUNNING');
+
+---- create above / drop below ----
+
+drop index idx_agp_execution_created_by_in_flight;
+drop table agp_concurrency_slot;
+drop table agp_rate_limit;
@@ -0,0 +1,33 @@
+-- Create usage ledger table, one row per completed query whatever the API used
+
+create table agp_usage (
+    id bigserial primary key,
+    created_at timestamp with time zone not null default now(),
+    quota_key text not null,
+    tier text not null,
+    source text not null,
+    execution_id bigint,
+    rows_read bigint not null default 0,
+    bytes_read bigint not null default 0,
+    elapsed_ms bigint not null default 0,
+    result_rows bigint not null default 0,
+    result_bytes bigint not null default 0
+);
+
+-- enum-like constraint
+alter table agp_usage add constraint const_agp_usage_source
+check (
+  source in (
+    'ASYNC',
+    'SYNC',
+    'CHPROXY'
+  )
+);
+
+-- ensures usage can be aggregated per quota key and period fast
+create index idx_agp_usage_quota_key_created_at
+on agp_usage (quota_key, created_at);
+
+---- create above / drop below ----
+
+drop table agp_usage;
@@ -0,0 +1,46 @@
+-- Create append-only audit log table, it is not subject to execution GC
+
+create table agp_audit_log (
+    id bigserial primary key,
+    created_at timestamp with time zone not null default now(),
+    source text not null,
+    outcome text not null,
+    subject text not null,
+    quota_key text not null,
+    client_ip text not null,
+    tier text not null,
+    execution_id bigint,
+    query_hash text not null,
+    query text not null,
+    status_code integer not null,
+    error text not null,
+    started_at timestamp with time zone not null,
+    completed_at timestamp with time zone
+);
+
+create index idx_agp_audit_log_quota_key_created_at
+on agp_audit_log (quota_key, created_at);
+
+create index idx_agp_audit_log_execution_id
+on agp_audit_log (execution_id)
+where execution_id is not null;
+
+-- audit rows are immutable
+
+create or replace function fn_agp_audit_log_immutable()
+returns trigger
+as $$
+  begin
+    raise exception 'agp_audit_log is append-only';
+  end;
+$$ language plpgsql;
+
+create trigger trgr_before_update_or_delete_agp_audit_log
+before update or delete on agp_audit_log
+for each row
+execute function fn_agp_audit_log_immutable();
+
+---- create above / drop below ----
+
+drop table agp_audit_log;
+drop function fn_agp_audit_log_immutable;
@@ -0,0 +1,24 @@
+-- Indexes backing keyset pagination of execution listings, ordered by (created_at, id)
+
+-- ensures a creator can page through its executions fast
+create index idx_agp_execution_created_by_created_at
+on agp_execution (created_by, created_at desc, id desc);
+
+-- ensures a creator can filter its executions by query_id prefix
+create index idx_agp_execution_created_by_query_id
+on agp_execution (created_by, query_id text_pattern_ops);
+
+-- ensures operators can page through executions of a given status fast
+create index idx_agp_execution_status_created_at
+on agp_execution (status, created_at desc, id desc);
+
+-- ensures operators can page through all executions fast
+create index idx_agp_execution_created_at
+on agp_execution (created_at desc, id desc);
+
+---- create above / drop below ----
+
+drop index idx_agp_execution_created_at;
+drop index idx_agp_execution_status_created_at;
+drop index idx_agp_execution_created_by_query_id;
+drop index idx_agp_execution_created_by_created_at;
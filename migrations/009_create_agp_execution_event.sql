@@ -0,0 +1,35 @@
+-- Create execution lifecycle event table, appended on each execution transition
+
+create table agp_execution_event (
+    id bigserial primary key,
+    execution_id bigint not null references agp_execution (id) on delete cascade,
+    created_at timestamp with time zone not null default now(),
+    type text not null,
+    actor text,
+    status text,
+    data jsonb
+);
+
+-- enum-like constraint
+alter table agp_execution_event add constraint const_agp_execution_event_type
+check (
+  type in (
+    'CREATED',
+    'COLLAPSED',
+    'PICKED',
+    'PROGRESS',
+    'REQUEUED',
+    'COMPLETED',
+    'CANCELED',
+    'DEAD',
+    'EXPIRED'
+  )
+);
+
+-- ensures the history of an execution can be read fast
+create index idx_agp_execution_event_execution_id
+on agp_execution_event (execution_id, id);
+
+---- create above / drop below ----
+
+drop table agp_execution_event;
@@ -0,0 +1,12 @@
+-- Per-execution result retention: the TTL requested at creation, the resulting expiration
+-- once completed, and a flag protecting an execution from GC
+
+alter table agp_execution add column ttl interval;
+alter table agp_execution add column expires_at timestamp with time zone;
+alter table agp_execution add column pinned boolean not null default false;
+
+---- create above / drop below ----
+
+alter table agp_execution drop column pinned;
+alter table agp_execution drop column expires_at;
+alter table agp_execution drop column ttl;
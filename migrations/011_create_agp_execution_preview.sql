@@ -0,0 +1,12 @@
+-- First rows of successful execution results, kept apart from agp_execution so that listings
+-- do not load them
+
+create table agp_execution_preview (
+    execution_id bigint primary key references agp_execution (id) on delete cascade,
+    data jsonb not null,
+    complete boolean not null
+);
+
+---- create above / drop below ----
+
+drop table agp_execution_preview;
@@ -0,0 +1,8 @@
+-- Flags audit events whose query text was cut at the capture limit, the query hash being computed
+-- on the captured text only
+
+alter table agp_audit_log add column query_truncated boolean not null default false;
+
+---- create above / drop below ----
+
+alter table agp_audit_log drop column query_truncated;
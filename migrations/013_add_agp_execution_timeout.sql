@@ -0,0 +1,7 @@
+-- Per-execution query timeout, e.g. carried over from a promoted sync query
+
+alter table agp_execution add column timeout interval;
+
+---- create above / drop below ----
+
+alter table agp_execution drop column timeout;
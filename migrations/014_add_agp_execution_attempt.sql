@@ -0,0 +1,8 @@
+-- Number of times an execution was picked, so that a requeued execution picked again does not
+-- share its result path, backend query id and ownership with the run it replaces
+
+alter table agp_execution add column attempt integer not null default 0;
+
+---- create above / drop below ----
+
+alter table agp_execution drop column attempt;
@@ -0,0 +1,14 @@
+package migrations
+
+import "github.com/jackc/tern/v2/migrate"
+
+// Version returns the schema version the embedded migrations lead to.
+func Version() (int32, error) {
+	paths, err := migrate.FindMigrations(FS)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return int32(len(paths)), nil
+}
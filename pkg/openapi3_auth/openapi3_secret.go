@@ -2,7 +2,9 @@ package openapi3_auth
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
+	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3filter"
 )
@@ -23,14 +25,17 @@ func OpenAPI3Secret(secret string, conf OpenAPI3SecretConfig) openapi3filter.Aut
 			v = username
 		} else {
 			v = ai.RequestValidationInput.Request.Header.Get("Authorization")
+			v = strings.TrimPrefix(v, "Bearer ")
+			v = strings.TrimPrefix(v, "bearer ")
 		}
 
 		if len(v) == 0 && conf.AllowEmpty {
 			return nil
 		}
 
-		if len(v) == 0 || v != secret {
-			return fmt.Errorf("invalid secret: %s", v)
+		// the presented value is never echoed back, and compared in constant time
+		if len(v) == 0 || subtle.ConstantTimeCompare([]byte(v), []byte(secret)) != 1 {
+			return fmt.Errorf("invalid secret")
 		}
 
 		return nil